@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
@@ -86,11 +87,24 @@ var templateFuncs = template.FuncMap{
 		}
 	},
 	"formatNonFraction": func(nf *ixbrl.NonFraction) string {
-		val := nf.ScaledNumber()
+		val, err := nf.ScaledValue()
+		if err != nil {
+			return "N/A"
+		}
 		return printer.Sprintf("$%.0f", val)
 	},
+	"formatNonFractionCount": func(nf *ixbrl.NonFraction) string {
+		val, err := nf.ScaledValue()
+		if err != nil {
+			return "N/A"
+		}
+		return printer.Sprintf("%.0f", val)
+	},
 	"formatNonFractionPerEmployee": func(nf *ixbrl.NonFraction, employeeCount int) template.HTML {
-		val := nf.ScaledNumber()
+		val, err := nf.ScaledValue()
+		if err != nil {
+			return "N/A"
+		}
 		formatted := printer.Sprintf("$%.0f", val)
 		
 		if employeeCount > 0 {
@@ -101,6 +115,16 @@ var templateFuncs = template.FuncMap{
 		
 		return template.HTML(formatted)
 	},
+	// sourceLink links to the spot in the filing a value was read from, with
+	// the text it was read from as a tooltip
+	"sourceLink": func(p *ixbrl.Provenance) template.HTML {
+		url := p.URL()
+		if url == "" {
+			return ""
+		}
+		return template.HTML(fmt.Sprintf(`<a class="source" target="_blank" href="%s" title="%s">source</a>`,
+			template.HTMLEscapeString(url), template.HTMLEscapeString(p.Snippet)))
+	},
 }
 
 var tpl = template.Must(template.New("facts").Funcs(templateFuncs).Parse(templateHTML))
@@ -108,6 +132,32 @@ var indexTemplate = template.Must(template.New("index").Parse(indexHTML))
 
 const cacheMaxAge = 30 * 24 * time.Hour // 1 month
 
+// tickersMaxAge is how often the SEC's ticker list is refreshed, so that
+// recent IPOs and renamed companies can be found
+const tickersMaxAge = 24 * time.Hour
+
+// filingTypes lists the forms downloaded for each company, and how many
+// of the most recent filings of each to fetch so trends can be shown.
+// Where match is set, only the filings it accepts are counted and fetched.
+// Event filings only add to the page's lists of recent events, so few are
+// fetched, and they're downloaded alongside the others rather than after.
+var filingTypes = []struct {
+	form  string
+	count int
+	match func(edgar.Filing) bool
+	event bool
+}{
+	{"10-K", 5, nil, false},
+	{"10-Q", 1, nil, false},
+	{"DEF 14A", 5, nil, false},
+	// Restructurings and layoffs, and executive departures and appointments
+	{"8-K", 3, func(f edgar.Filing) bool {
+		return f.Form == "8-K" && f.HasItem(edgar.Item8KRestructuring, edgar.Item8KOfficerChanges)
+	}, true},
+	// Insider transactions, which include executives selling stock
+	{"4", 5, func(f edgar.Filing) bool { return f.Form == "4" }, true},
+}
+
 // OrganizationItem represents a simplified organization with just title and path
 type OrganizationItem struct {
 	Title string `json:"title"` // Company/organization name
@@ -118,15 +168,31 @@ type Server struct {
 	db        *db.DB
 	client    *edgar.EdgarClient
 	irsClient *irs.IRSClient
+	registry  *edgar.Registry
 }
 
 func NewServer(database *db.DB) *Server {
-	// Initialize Edgar client for network requests
-	userAgent := "Jeff Sisson (jeff@bigboy.us)"
-	client := edgar.NewEdgarClient(userAgent, 10)
+	// Initialize Edgar client for network requests; the SEC asks that
+	// automated clients identify themselves with a contact email
+	userAgent := os.Getenv("USER_AGENT")
+	if userAgent == "" {
+		userAgent = "Jeff Sisson (jeff@bigboy.us)"
+	}
+	edgarOpts := []edgar.Option{edgar.WithUserAgent(userAgent), edgar.WithRateLimit(10)}
+	if url := os.Getenv("EDGAR_DATA_URL"); url != "" {
+		edgarOpts = append(edgarOpts, edgar.WithDataURL(url))
+	}
+	if url := os.Getenv("EDGAR_SITE_URL"); url != "" {
+		edgarOpts = append(edgarOpts, edgar.WithSiteURL(url))
+	}
+	client := edgar.NewClient(edgarOpts...)
 	
 	// Initialize IRS client for 2024 data
-	irsClient, err := irs.NewIRSClient(os.Getenv("CACHE_DIR"), "2024")
+	irsOpts := []irs.Option{irs.WithUserAgent(userAgent)}
+	if url := os.Getenv("IRS_BASE_URL"); url != "" {
+		irsOpts = append(irsOpts, irs.WithBaseURL(url))
+	}
+	irsClient, err := irs.NewIRSClient(os.Getenv("CACHE_DIR"), "2024", irsOpts...)
 	if err != nil {
 		log.Fatalf("Failed to initialize IRS client: %v", err)
 	}
@@ -135,40 +201,71 @@ func NewServer(database *db.DB) *Server {
 		db:        database,
 		client:    client,
 		irsClient: irsClient,
+		registry:  edgar.DefaultRegistry,
+	}
+
+	// Prefer the last refreshed ticker list over the embedded snapshot
+	tickers, err := database.ListTickers()
+	if err != nil {
+		log.Printf("Warning: Failed to load stored tickers: %v", err)
+	} else if len(tickers) > 0 {
+		server.registry.Replace(tickers)
 	}
 	
 	// Populate search cache if empty
 	if err := server.ensureSearchCachePopulated(); err != nil {
 		log.Printf("Warning: Failed to populate search cache: %v", err)
 	}
+
+	go server.refreshTickersPeriodically()
 	
 	return server
 }
 
-// ensureSearchCachePopulated populates the search cache if it's empty
-func (s *Server) ensureSearchCachePopulated() error {
-	count, err := s.db.GetSearchCacheCount()
+// refreshTickersPeriodically keeps the ticker registry up to date with the
+// SEC's current list, checking whether it has gone stale once an hour
+func (s *Server) refreshTickersPeriodically() {
+	for {
+		stale, err := s.db.AreTickersStale(tickersMaxAge)
+		if err != nil {
+			log.Printf("Error checking tickers staleness: %v", err)
+		} else if stale {
+			if err := s.refreshTickers(context.Background()); err != nil {
+				log.Printf("Warning: Failed to refresh tickers: %v", err)
+			}
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
+// refreshTickers loads the SEC's current ticker list into the database and
+// registry, then rebuilds the search cache so new companies can be found
+func (s *Server) refreshTickers(ctx context.Context) error {
+	log.Println("Refreshing tickers...")
+
+	tickers, err := s.client.LoadTickers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get search cache count: %w", err)
+		return fmt.Errorf("failed to load tickers: %w", err)
 	}
-	
-	if count > 0 {
-		log.Printf("Search cache already populated with %d items", count)
-		return nil
+	if err := s.db.StoreTickers(tickers); err != nil {
+		return fmt.Errorf("failed to store tickers: %w", err)
 	}
-	
-	log.Println("Populating search cache...")
-	
-	// Clear cache first
-	if err := s.db.ClearSearchCache(); err != nil {
-		return fmt.Errorf("failed to clear search cache: %w", err)
+	s.registry.Replace(tickers)
+
+	if err := s.db.ReplaceSearchCache(s.searchCacheItems()); err != nil {
+		return fmt.Errorf("failed to rebuild search cache: %w", err)
 	}
-	
-	// Prepare batch items
+
+	log.Printf("Refreshed %d tickers", len(tickers))
+	return nil
+}
+
+// searchCacheItems lists every SEC company and IRS nonprofit for the search cache
+func (s *Server) searchCacheItems() []db.SearchCacheItem {
 	var items []db.SearchCacheItem
 	
 	// Add Edgar data
-	for _, ticker := range edgar.TickersData {
+	for _, ticker := range s.registry.Tickers() {
 		items = append(items, db.SearchCacheItem{
 			Title:      ticker.Title,
 			Path:       fmt.Sprintf("/ticker/%s", ticker.Ticker),
@@ -189,9 +286,31 @@ func (s *Server) ensureSearchCachePopulated() error {
 			SourceType: "IRS",
 		})
 	}
+
+	return items
+}
+
+// ensureSearchCachePopulated populates the search cache if it's empty
+func (s *Server) ensureSearchCachePopulated() error {
+	count, err := s.db.GetSearchCacheCount()
+	if err != nil {
+		return fmt.Errorf("failed to get search cache count: %w", err)
+	}
+	
+	if count > 0 {
+		log.Printf("Search cache already populated with %d items", count)
+		return nil
+	}
+	
+	log.Println("Populating search cache...")
+	
+	// Clear cache first
+	if err := s.db.ClearSearchCache(); err != nil {
+		return fmt.Errorf("failed to clear search cache: %w", err)
+	}
 	
 	// Store items in batches
-	if err := s.db.StoreSearchCacheItems(items); err != nil {
+	if err := s.db.StoreSearchCacheItems(s.searchCacheItems()); err != nil {
 		return fmt.Errorf("failed to store search cache items: %w", err)
 	}
 	
@@ -216,7 +335,7 @@ func (s *Server) handleTicker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Convert ticker to CIK
-	cik, err := edgar.Ticker2CIK(ticker)
+	cik, err := s.registry.Ticker2CIK(ticker)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Ticker %s not found: %v", ticker, err), http.StatusNotFound)
 		return
@@ -235,7 +354,7 @@ func (s *Server) handleCik(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Convert ticker to CIK
-	ticker, err := edgar.CIK2Ticker(cik)
+	ticker, err := s.registry.CIK2Ticker(cik)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("cik %s not found: %v", cik, err), http.StatusNotFound)
 		return
@@ -269,7 +388,7 @@ func (s *Server) handleOrganizationsJSON(w http.ResponseWriter, r *http.Request)
 	var organizations []OrganizationItem
 	
 	// Add Edgar data
-	for _, ticker := range edgar.TickersData {
+	for _, ticker := range s.registry.Tickers() {
 		organizations = append(organizations, OrganizationItem{
 			Title: ticker.Title,
 			Path:  fmt.Sprintf("/ticker/%s", ticker.Ticker),
@@ -376,7 +495,7 @@ func (s *Server) handleFilings(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Facts for CIK %s are stale or missing, fetching from network", cik)
 		factData, err = s.downloadAndProcessFacts(r.Context(), cik, ticker)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to process facts: %v", err), http.StatusInternalServerError)
+			http.Error(w, fmt.Sprintf("Failed to process facts: %v", err), edgarErrorStatus(w, err))
 			return
 		}
 
@@ -395,6 +514,25 @@ func (s *Server) handleFilings(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// edgarErrorStatus picks the HTTP status to respond with when fetching
+// data from EDGAR failed, setting Retry-After if EDGAR asked us to back off
+func edgarErrorStatus(w http.ResponseWriter, err error) int {
+	switch {
+	case errors.Is(err, edgar.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, edgar.ErrRateLimited):
+		var statusErr *edgar.StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(statusErr.RetryAfter.Seconds())))
+		}
+		return http.StatusServiceUnavailable
+	case errors.Is(err, edgar.ErrUnavailable):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // downloadAndProcessFacts downloads and processes Edgar data from the network
 func (s *Server) downloadAndProcessFacts(ctx context.Context, cik, ticker string) (*facts.Facts, error) {
 	log.Printf("Downloading submissions for CIK %s...", cik)
@@ -404,29 +542,89 @@ func (s *Server) downloadAndProcessFacts(ctx context.Context, cik, ticker string
 	if err != nil {
 		return nil, fmt.Errorf("failed to load submissions: %w", err)
 	}
+	s.registry.AddSubmissions(submissions)
 
 	// Search for filings
-	filingTypes := []string{"10-K", "10-Q", "DEF 14A"}
-	var foundFilings []edgar.Filing
+	var foundFilings, eventFilings []edgar.Filing
 	for _, filingType := range filingTypes {
-		filing, found := submissions.Filings.Search(cik, filingType)
-		if found {
-			foundFilings = append(foundFilings, filing)
-			log.Printf("Found %s filing: %s", filingType, filing.AccessionNumber)
+		var filings []edgar.Filing
+		if filingType.match != nil {
+			filings = submissions.Filings.SearchFunc(cik, filingType.count, filingType.match)
+		} else {
+			filings = submissions.Filings.SearchN(cik, filingType.form, filingType.count)
 		}
+		for _, filing := range filings {
+			if filingType.event {
+				eventFilings = append(eventFilings, filing)
+			} else {
+				foundFilings = append(foundFilings, filing)
+			}
+			log.Printf("Found %s filing: %s", filingType.form, filing.AccessionNumber)
+		}
+	}
+
+	if len(foundFilings) == 0 && len(eventFilings) == 0 {
+		return nil, fmt.Errorf("no relevant filings found for CIK %s: %w", cik, edgar.ErrNotFound)
+	}
+
+	// Download the event filings in the meantime, so that they don't hold
+	// up the annual reports and proxies the page's main figures come from
+	type downloaded struct {
+		docs []edgar.Document
+		err  error
+	}
+	events := make(chan downloaded, 1)
+	go func() {
+		docs, err := s.downloadDocuments(ctx, cik, eventFilings)
+		events <- downloaded{docs, err}
+	}()
+	filingDocs, downloadErr := s.downloadDocuments(ctx, cik, foundFilings)
+	eventDocs := <-events
+	filingDocs = append(filingDocs, eventDocs.docs...)
+	if downloadErr == nil {
+		downloadErr = eventDocs.err
+	}
+
+	if len(filingDocs) == 0 {
+		return nil, fmt.Errorf("failed to download any documents for CIK %s: %w", cik, downloadErr)
 	}
 
-	if len(foundFilings) == 0 {
-		return nil, fmt.Errorf("no relevant filings found for CIK %s", cik)
+	// Get company name
+	companyName, err := s.registry.Ticker2CompanyName(ticker)
+	if err != nil {
+		log.Printf("Warning: Could not get company name for ticker %s: %v", ticker, err)
+		companyName = "" // Use empty string if not found
+	}
+
+	// Extract facts
+	factData, err := facts.ExtractFacts(cik, ticker, companyName, filingDocs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer the structured XBRL financial data where the SEC has it
+	companyFacts, err := s.client.LoadCompanyFacts(ctx, cik)
+	if err != nil {
+		log.Printf("Warning: Could not load company facts for CIK %s, using scraped values: %v", cik, err)
+	} else {
+		factData.AddCompanyFacts(companyFacts)
 	}
 
-	// Download documents
+	return factData, nil
+}
+
+// downloadDocuments downloads the documents of filings, skipping any that
+// fail to download. It returns the last download error, if any.
+func (s *Server) downloadDocuments(ctx context.Context, cik string, filings []edgar.Filing) ([]edgar.Document, error) {
 	var filingDocs []edgar.Document
-	for _, filing := range foundFilings {
+	var downloadErr error
+	loadedLinkbases := false
+	for _, filing := range filings {
 		log.Printf("Downloading document for %s filing...", filing.Form)
 		content, err := s.client.LoadDocument(ctx, cik, filing)
 		if err != nil {
 			log.Printf("Failed to download %s document: %v", filing.Form, err)
+			downloadErr = err
 			continue
 		}
 		
@@ -434,22 +632,23 @@ func (s *Server) downloadAndProcessFacts(ctx context.Context, cik, ticker string
 			Filing:       filing,
 			DocumentFile: content,
 		}
-		filingDocs = append(filingDocs, doc)
-	}
 
-	if len(filingDocs) == 0 {
-		return nil, fmt.Errorf("failed to download any documents for CIK %s", cik)
-	}
-
-	// Get company name
-	companyName, err := edgar.Ticker2CompanyName(ticker)
-	if err != nil {
-		log.Printf("Warning: Could not get company name for ticker %s: %v", ticker, err)
-		companyName = "" // Use empty string if not found
+		// Label the financial series using the most recent annual report's
+		// own linkbases, which include any company-specific concepts, and
+		// check that its totals add up
+		if filing.Form == "10-K" && filing.IsInlineXBLR == 1 && !loadedLinkbases {
+			labels, calculations, err := s.client.LoadLinkbases(ctx, cik, filing)
+			if err != nil {
+				log.Printf("Warning: Could not load linkbases for %s: %v", filing.AccessionNumber, err)
+			} else {
+				doc.LabelLinkbase = labels
+				doc.CalculationLinkbase = calculations
+				loadedLinkbases = true
+			}
+		}
+		filingDocs = append(filingDocs, doc)
 	}
-
-	// Extract facts
-	return facts.ExtractFacts(cik, ticker, companyName, filingDocs)
+	return filingDocs, downloadErr
 }
 
 // handleHealth handles GET /health
@@ -104,6 +104,21 @@ func (db *DB) createTables() error {
 		return fmt.Errorf("failed to create irs_returns table: %w", err)
 	}
 
+	// Create tickers table, holding the most recently refreshed SEC ticker list
+	tickersSQL := `
+		CREATE TABLE IF NOT EXISTS tickers (
+			ticker TEXT PRIMARY KEY,
+			cik INTEGER NOT NULL,
+			title TEXT NOT NULL,
+			exchange TEXT DEFAULT '',
+			rank INTEGER NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+	`
+	if _, err := db.conn.Exec(tickersSQL); err != nil {
+		return fmt.Errorf("failed to create tickers table: %w", err)
+	}
+
 	// Create search cache table using FTS for efficient searching
 	searchCacheSQL := `
 		CREATE VIRTUAL TABLE IF NOT EXISTS search_cache USING fts5(
@@ -456,6 +471,84 @@ func (db *DB) ListIRSReturnEINs() ([]string, error) {
 	return eins, nil
 }
 
+// StoreTickers replaces the stored ticker list with a freshly loaded one
+func (db *DB) StoreTickers(tickers []edgar.TickerData) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM tickers"); err != nil {
+		return fmt.Errorf("failed to clear tickers: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO tickers (ticker, cik, title, exchange, rank, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for rank, t := range tickers {
+		if _, err := stmt.Exec(t.Ticker, t.CIKStr, t.Title, t.Exchange, rank); err != nil {
+			return fmt.Errorf("failed to store ticker %s: %w", t.Ticker, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListTickers returns the stored ticker list, in the SEC's order
+func (db *DB) ListTickers() ([]edgar.TickerData, error) {
+	query := `SELECT ticker, cik, title, exchange FROM tickers ORDER BY rank`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickers: %w", err)
+	}
+	defer rows.Close()
+
+	var tickers []edgar.TickerData
+	for rows.Next() {
+		var t edgar.TickerData
+		if err := rows.Scan(&t.Ticker, &t.CIKStr, &t.Title, &t.Exchange); err != nil {
+			return nil, fmt.Errorf("failed to scan ticker row: %w", err)
+		}
+		tickers = append(tickers, t)
+	}
+	return tickers, nil
+}
+
+// AreTickersStale checks if the stored ticker list is older than the specified duration
+func (db *DB) AreTickersStale(maxAge time.Duration) (bool, error) {
+	query := "SELECT updated_at FROM tickers ORDER BY updated_at LIMIT 1"
+
+	var updatedAt string
+	err := db.conn.QueryRow(query).Scan(&updatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return true, nil // No tickers stored, consider stale
+		}
+		return false, fmt.Errorf("failed to query tickers timestamp: %w", err)
+	}
+
+	// Parse the timestamp (SQLite CURRENT_TIMESTAMP returns RFC3339 format)
+	timestamp, err := time.Parse(time.RFC3339, updatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	// Check if data is older than maxAge
+	return time.Since(timestamp) > maxAge, nil
+}
+
 // SearchCacheItem represents a single search cache entry
 type SearchCacheItem struct {
 	Title      string
@@ -525,6 +618,41 @@ func (db *DB) storeSearchCacheBatch(items []SearchCacheItem) error {
 	return nil
 }
 
+// ReplaceSearchCache rebuilds the search cache from the given items in a
+// single transaction, so searches never see a partially built cache
+func (db *DB) ReplaceSearchCache(items []SearchCacheItem) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM search_cache"); err != nil {
+		return fmt.Errorf("failed to clear search cache: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO search_cache (title, path, source_type, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, item := range items {
+		if _, err := stmt.Exec(item.Title, item.Path, item.SourceType); err != nil {
+			return fmt.Errorf("failed to execute statement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // ClearSearchCache clears all search cache entries
 func (db *DB) ClearSearchCache() error {
 	query := "DELETE FROM search_cache"
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +18,16 @@ import (
 type EdgarClient struct {
 	userAgent  string
 	httpClient *http.Client
+
+	// Base URLs for the data.sec.gov APIs and the www.sec.gov site,
+	// which hosts the filing archives and ticker files
+	dataURL string
+	siteURL string
+
+	// Retry policy for rate limited, unavailable or failed requests
+	maxRetries    int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
 }
 
 // rateLimitedTransport wraps an HTTP transport with rate limiting
@@ -34,102 +46,330 @@ func (r *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, err
 
 // NewEdgarClient creates a new Edgar API client with rate limiting
 func NewEdgarClient(userAgent string, rateLimit int) *EdgarClient {
-	if rateLimit <= 0 {
-		rateLimit = 10 // Default to 10 requests per second
-	}
+	return NewClient(WithUserAgent(userAgent), WithRateLimit(rateLimit))
+}
 
-	// Create rate-limited transport
-	transport := &rateLimitedTransport{
-		transport: http.DefaultTransport,
-		limiter:   rate.NewLimiter(rate.Limit(rateLimit), rateLimit),
+// NewClient creates a new Edgar API client configured by opts. By default
+// it talks to the SEC's public servers, rate limited to 10 requests per
+// second as the SEC asks, so WithUserAgent is the only option most callers
+// need: the SEC requires a user agent identifying who is making requests.
+func NewClient(opts ...Option) *EdgarClient {
+	o := options{
+		rateLimit:     10,
+		dataURL:       "https://data.sec.gov",
+		siteURL:       "https://www.sec.gov",
+		timeout:       30 * time.Second,
+		maxRetries:    4,
+		retryDelay:    500 * time.Millisecond,
+		maxRetryDelay: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rateLimit <= 0 {
+		o.rateLimit = 10 // Default to 10 requests per second
 	}
 
-	// Create HTTP client with rate-limited transport
-	httpClient := &http.Client{
-		Timeout:   30 * time.Second,
-		Transport: transport,
+	// Create HTTP client with rate-limited transport, based on any client
+	// or transport the caller provided. The client has no overall timeout,
+	// which would also cut off documents streamed by OpenDocument that take
+	// longer to download: only connecting and waiting for the response's
+	// headers are timed out, and callers limit the rest with their context.
+	httpClient := &http.Client{}
+	if o.httpClient != nil {
+		clientCopy := *o.httpClient
+		httpClient = &clientCopy
+	}
+	transport := o.transport
+	if transport == nil {
+		transport = httpClient.Transport
+	}
+	if transport == nil {
+		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+		defaultTransport.DialContext = (&net.Dialer{Timeout: o.timeout, KeepAlive: 30 * time.Second}).DialContext
+		defaultTransport.TLSHandshakeTimeout = o.timeout
+		defaultTransport.ResponseHeaderTimeout = o.timeout
+		transport = defaultTransport
+	}
+	httpClient.Transport = &rateLimitedTransport{
+		transport: transport,
+		limiter:   rate.NewLimiter(rate.Limit(o.rateLimit), o.rateLimit),
 	}
 
 	return &EdgarClient{
-		userAgent:  userAgent,
-		httpClient: httpClient,
+		userAgent:     o.userAgent,
+		httpClient:    httpClient,
+		dataURL:       strings.TrimSuffix(o.dataURL, "/"),
+		siteURL:       strings.TrimSuffix(o.siteURL, "/"),
+		maxRetries:    o.maxRetries,
+		retryDelay:    o.retryDelay,
+		maxRetryDelay: o.maxRetryDelay,
+	}
+}
+
+type options struct {
+	userAgent     string
+	rateLimit     int
+	dataURL       string
+	siteURL       string
+	httpClient    *http.Client
+	transport     http.RoundTripper
+	timeout       time.Duration
+	maxRetries    int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+}
+
+// Option configures an EdgarClient created with NewClient
+type Option func(*options)
+
+// WithUserAgent sets the User-Agent sent with every request, which the SEC
+// requires to include a name and contact email address
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) { o.userAgent = userAgent }
+}
+
+// WithRateLimit sets the maximum number of requests per second
+func WithRateLimit(requestsPerSecond int) Option {
+	return func(o *options) { o.rateLimit = requestsPerSecond }
+}
+
+// WithDataURL replaces https://data.sec.gov, which serves the submissions
+// and XBRL APIs, e.g. to point the client at a mirror or test server
+func WithDataURL(url string) Option {
+	return func(o *options) { o.dataURL = url }
+}
+
+// WithSiteURL replaces https://www.sec.gov, which serves the filing
+// archives and ticker files, e.g. to point the client at a mirror or test server
+func WithSiteURL(url string) Option {
+	return func(o *options) { o.siteURL = url }
+}
+
+// WithHTTPClient sets the HTTP client used to make requests. Its transport
+// is wrapped with the client's rate limiting.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) { o.httpClient = client }
+}
+
+// WithTransport sets the transport used to make requests, e.g. to record
+// and replay responses. It is wrapped with the client's rate limiting.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) { o.transport = transport }
+}
+
+// WithTimeout sets how long to wait to connect to EDGAR and for it to
+// start responding, 30 seconds by default. Reading the response isn't
+// timed out, as large documents may take longer to download: use the
+// request's context to limit that. It doesn't apply to the transport of
+// a client or transport set with WithHTTPClient or WithTransport.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithRetryPolicy sets how many times failed requests are retried, and the
+// initial and maximum delay between attempts. Requests that EDGAR asks
+// to retry after longer than the maximum delay fail with a *StatusError.
+func WithRetryPolicy(maxRetries int, retryDelay, maxRetryDelay time.Duration) Option {
+	return func(o *options) {
+		o.maxRetries = maxRetries
+		o.retryDelay = retryDelay
+		o.maxRetryDelay = maxRetryDelay
 	}
 }
 
-// LoadSubmissions fetches and parses Edgar submissions data for a given CIK number
+// LoadSubmissions fetches and parses Edgar submissions data for a given CIK number.
+// Older filings that the SEC pages out into separate files are fetched and
+// merged, so the returned Filings cover the filer's complete history.
 func (c *EdgarClient) LoadSubmissions(ctx context.Context, cik string) (*Submissions, error) {
 	// Format CIK to 10 digits with leading zeros
 	formattedCIK := fmt.Sprintf("%010s", cik)
 
 	// Construct the API URL
-	url := fmt.Sprintf("https://data.sec.gov/submissions/CIK%s.json", formattedCIK)
-		fmt.Println(url)
+	url := fmt.Sprintf("%s/submissions/CIK%s.json", c.dataURL, formattedCIK)
 
-	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var submissions Submissions
+	if err := c.getJSON(ctx, url, &submissions); err != nil {
+		return nil, err
+	}
+
+	// Fetch any additional pages of older filings
+	for _, file := range submissions.Filings.Files {
+		var page FilingColumns
+		url := fmt.Sprintf("%s/submissions/%s", c.dataURL, file.Name)
+		if err := c.getJSON(ctx, url, &page); err != nil {
+			return nil, fmt.Errorf("failed to load submissions page %s: %w", file.Name, err)
+		}
+		submissions.Filings.Merge(page)
 	}
 
-	// Set User-Agent header
-	req.Header.Set("User-Agent", c.userAgent)
+	return &submissions, nil
+}
+
+// LoadCompanyFacts fetches every XBRL fact a company has reported, across
+// all of its filings, from the SEC's companyfacts API
+func (c *EdgarClient) LoadCompanyFacts(ctx context.Context, cik string) (*CompanyFacts, error) {
+	url := fmt.Sprintf("%s/api/xbrl/companyfacts/CIK%010s.json", c.dataURL, cik)
+
+	var companyFacts CompanyFacts
+	if err := c.getJSON(ctx, url, &companyFacts); err != nil {
+		return nil, err
+	}
+	return &companyFacts, nil
+}
+
+// LoadCompanyConcept fetches the values a company has reported for a single
+// XBRL concept, e.g. taxonomy "us-gaap" and tag "NetIncomeLoss"
+func (c *EdgarClient) LoadCompanyConcept(ctx context.Context, cik, taxonomy, tag string) (*CompanyConcept, error) {
+	url := fmt.Sprintf("%s/api/xbrl/companyconcept/CIK%010s/%s/%s.json", c.dataURL, cik, taxonomy, tag)
+
+	var concept CompanyConcept
+	if err := c.getJSON(ctx, url, &concept); err != nil {
+		return nil, err
+	}
+	return &concept, nil
+}
 
-	// Make HTTP request (rate limiting handled by transport)
-	resp, err := c.httpClient.Do(req)
+// LoadTickers fetches the SEC's current list of company tickers, annotated
+// with the exchange each ticker is listed on
+func (c *EdgarClient) LoadTickers(ctx context.Context) ([]TickerData, error) {
+	var raw json.RawMessage
+	if err := c.getJSON(ctx, c.siteURL+"/files/company_tickers.json", &raw); err != nil {
+		return nil, fmt.Errorf("failed to load company tickers: %w", err)
+	}
+	tickers, err := parseTickers(raw)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data from SEC API: %w", err)
+		return nil, fmt.Errorf("failed to parse company tickers: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check if request was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("SEC API returned status %d", resp.StatusCode)
+	// The exchanges file is laid out as rows of values, described by "fields"
+	var exchanges struct {
+		Fields []string `json:"fields"`
+		Data   [][]any  `json:"data"`
+	}
+	if err := c.getJSON(ctx, c.siteURL+"/files/company_tickers_exchange.json", &exchanges); err != nil {
+		return nil, fmt.Errorf("failed to load company ticker exchanges: %w", err)
+	}
+	tickerCol := slices.Index(exchanges.Fields, "ticker")
+	exchangeCol := slices.Index(exchanges.Fields, "exchange")
+	if tickerCol == -1 || exchangeCol == -1 {
+		return nil, fmt.Errorf("unexpected company ticker exchange fields %v", exchanges.Fields)
+	}
+	exchangeByTicker := map[string]string{}
+	for _, row := range exchanges.Data {
+		if len(row) <= tickerCol || len(row) <= exchangeCol {
+			continue
+		}
+		ticker, _ := row[tickerCol].(string)
+		exchange, _ := row[exchangeCol].(string)
+		exchangeByTicker[ticker] = exchange
+	}
+	for i := range tickers {
+		tickers[i].Exchange = exchangeByTicker[tickers[i].Ticker]
+	}
+
+	return tickers, nil
+}
+
+// getJSON fetches a JSON document from the SEC and decodes it into v
+func (c *EdgarClient) getJSON(ctx context.Context, url string, v any) error {
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
 	// Parse JSON response
-	var submissions Submissions
-	if err := json.NewDecoder(resp.Body).Decode(&submissions); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %w", err)
 	}
 
-	return &submissions, nil
+	return nil
 }
 
-// LoadDocument fetches a filing document using the Filing information
+// LoadDocument fetches a filing's primary document using the Filing
+// information. For XML forms such as Form 4 this is the XML as filed,
+// rather than EDGAR's HTML rendering of it.
 func (c *EdgarClient) LoadDocument(ctx context.Context, cik string, filing Filing) ([]byte, error) {
-	// Remove hyphens from accession number for URL formatting
-	accessionNumber := strings.ReplaceAll(filing.AccessionNumber, "-", "")
-	
-	// Construct the document URL
-	url := fmt.Sprintf("https://www.sec.gov/Archives/edgar/data/%s/%s/%s", 
-		cik, accessionNumber, filing.PrimaryDocument)
+	return c.LoadFilingDocument(ctx, cik, filing, filing.SourceDocument())
+}
 
-	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// OpenDocument is a streaming variant of LoadDocument, for documents too
+// large to comfortably hold in memory. The caller must close the reader.
+func (c *EdgarClient) OpenDocument(ctx context.Context, cik string, filing Filing) (io.ReadCloser, error) {
+	return c.OpenFilingDocument(ctx, cik, filing, filing.SourceDocument())
+}
+
+// LoadFilingIndex fetches the list of files in a filing's directory, such
+// as exhibits, the XBRL instance and linkbases, and FilingSummary.xml
+func (c *EdgarClient) LoadFilingIndex(ctx context.Context, cik string, filing Filing) (*FilingIndex, error) {
+	filing.CIK = cik
+
+	var index filingIndexJSON
+	if err := c.getJSON(ctx, c.siteURL+filing.archivePath("index.json"), &index); err != nil {
+		return nil, fmt.Errorf("failed to load filing index: %w", err)
 	}
-	
-	// Set User-Agent header
-	req.Header.Set("User-Agent", c.userAgent)
-	
-	// Make HTTP request (rate limiting handled by transport)
-	resp, err := c.httpClient.Do(req)
+	return index.index(filing), nil
+}
+
+// LoadLinkbases fetches a filing's XBRL label and calculation linkbases,
+// which give its concepts human-readable labels and say how they sum up.
+// Either is nil if the filing's directory doesn't have one.
+func (c *EdgarClient) LoadLinkbases(ctx context.Context, cik string, filing Filing) (labels, calculations []byte, err error) {
+	index, err := c.LoadFilingIndex(ctx, cik, filing)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch document from SEC: %w", err)
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
-	
-	// Check if request was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("SEC returned status %d for document request", resp.StatusCode)
+	load := func(t DocumentType) ([]byte, error) {
+		files := index.Find(t)
+		if len(files) == 0 {
+			return nil, nil
+		}
+		content, err := c.LoadFilingDocument(ctx, cik, filing, files[0].Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", t, err)
+		}
+		return content, nil
+	}
+	if labels, err = load(DocumentTypeLabelLinkbase); err != nil {
+		return nil, nil, err
+	}
+	if calculations, err = load(DocumentTypeCalculationLinkbase); err != nil {
+		return nil, nil, err
+	}
+	return labels, calculations, nil
+}
+
+// LoadFilingDocument fetches any named file from a filing's directory, e.g.
+// one of the files listed by LoadFilingIndex
+func (c *EdgarClient) LoadFilingDocument(ctx context.Context, cik string, filing Filing, name string) ([]byte, error) {
+	body, err := c.OpenFilingDocument(ctx, cik, filing, name)
+	if err != nil {
+		return nil, err
 	}
+	defer body.Close()
 	
 	// Read the document content
-	content, err := io.ReadAll(resp.Body)
+	content, err := io.ReadAll(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read document content: %w", err)
 	}
 	
 	return content, nil
 }
+
+// OpenFilingDocument is a streaming variant of LoadFilingDocument. The
+// returned reader yields the decompressed document as it is downloaded,
+// and the caller must close it.
+func (c *EdgarClient) OpenFilingDocument(ctx context.Context, cik string, filing Filing, name string) (io.ReadCloser, error) {
+	filing.CIK = cik
+
+	// Construct the document URL
+	url := c.siteURL + filing.archivePath(name)
+
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
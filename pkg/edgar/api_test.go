@@ -0,0 +1,179 @@
+package edgar
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientBaseURLs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/submissions/CIK0000320193.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"cik": "320193",
+			"name": "Apple Inc.",
+			"filings": {
+				"recent": {
+					"accessionNumber": ["0000320193-24-000123"],
+					"filingDate": ["2024-11-01"],
+					"reportDate": ["2024-09-28"],
+					"form": ["10-K"],
+					"fileNumber": ["001-36743"],
+					"isXBRL": [1],
+					"isInlineXBRL": [1],
+					"primaryDocument": ["aapl-20240928.htm"],
+					"primaryDocDescription": ["10-K"]
+				},
+				"files": [{"name": "CIK0000320193-submissions-001.json"}]
+			}
+		}`))
+	})
+	mux.HandleFunc("/submissions/CIK0000320193-submissions-001.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"accessionNumber": ["0000320193-23-000106"],
+			"filingDate": ["2023-11-03"],
+			"form": ["10-K"],
+			"primaryDocument": ["aapl-20230930.htm"]
+		}`))
+	})
+	mux.HandleFunc("/Archives/edgar/data/320193/000032019324000123/aapl-20240928.htm", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html></html>`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClient(
+		WithUserAgent("test (test@example.com)"),
+		WithDataURL(srv.URL),
+		WithSiteURL(srv.URL+"/"),
+		WithHTTPClient(srv.Client()),
+	)
+	ctx := context.Background()
+
+	submissions, err := client.LoadSubmissions(ctx, "320193")
+	require.NoError(t, err)
+	filings := submissions.Filings.SearchN("320193", "10-K", 0)
+	require.Len(t, filings, 2)
+	assert.Equal(t, "2023-11-03", filings[1].FilingDate)
+
+	document, err := client.LoadDocument(ctx, "320193", filings[0])
+	require.NoError(t, err)
+	assert.Equal(t, "<html></html>", string(document))
+}
+
+func TestCompressedDocuments(t *testing.T) {
+	document := bytes.Repeat([]byte("<p>Human Capital</p>"), 1000)
+	compress := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw-deflate": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+		"": nil,
+	}
+
+	for name, newWriter := range compress {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "gzip, deflate", r.Header.Get("Accept-Encoding"))
+				if newWriter == nil {
+					w.Write(document)
+					return
+				}
+				w.Header().Set("Content-Encoding", strings.TrimPrefix(name, "raw-"))
+				cw := newWriter(w)
+				cw.Write(document)
+				cw.Close()
+			}))
+			t.Cleanup(srv.Close)
+
+			client := NewClient(WithUserAgent("test (test@example.com)"), WithSiteURL(srv.URL))
+			filing := Filing{AccessionNumber: "0000320193-24-000123", PrimaryDocument: "aapl-20240928.htm"}
+
+			content, err := client.LoadDocument(context.Background(), "320193", filing)
+			require.NoError(t, err)
+			assert.Equal(t, document, content)
+
+			body, err := client.OpenDocument(context.Background(), "320193", filing)
+			require.NoError(t, err)
+			defer body.Close()
+			streamed, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, document, streamed)
+		})
+	}
+}
+
+func TestLoadLinkbases(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Archives/edgar/data/320193/000032019324000123/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"directory": {"name": "/Archives/edgar/data/320193/000032019324000123", "item": [
+			{"name": "aapl-20240928.htm", "size": "100"},
+			{"name": "aapl-20240928_cal.xml", "size": "10"},
+			{"name": "aapl-20240928_lab.xml", "size": "20"}
+		]}}`))
+	})
+	mux.HandleFunc("/Archives/edgar/data/320193/000032019324000123/aapl-20240928_lab.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<linkbase>labels</linkbase>`))
+	})
+	mux.HandleFunc("/Archives/edgar/data/320193/000032019324000123/aapl-20240928_cal.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<linkbase>calculations</linkbase>`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClient(WithUserAgent("test (test@example.com)"), WithSiteURL(srv.URL))
+	filing := Filing{AccessionNumber: "0000320193-24-000123", PrimaryDocument: "aapl-20240928.htm"}
+
+	labels, calculations, err := client.LoadLinkbases(context.Background(), "320193", filing)
+	require.NoError(t, err)
+	assert.Equal(t, `<linkbase>labels</linkbase>`, string(labels))
+	assert.Equal(t, `<linkbase>calculations</linkbase>`, string(calculations))
+}
+
+func TestOpenDocumentSlowBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/slow-headers.htm") {
+			time.Sleep(200 * time.Millisecond)
+		}
+		// The body trickles in for longer than the timeout
+		for range 4 {
+			w.Write([]byte("<p>Human Capital</p>"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient(
+		WithUserAgent("test (test@example.com)"),
+		WithSiteURL(srv.URL),
+		WithTimeout(100*time.Millisecond),
+		WithRetryPolicy(0, time.Millisecond, time.Millisecond),
+	)
+	filing := Filing{AccessionNumber: "0000320193-24-000123", PrimaryDocument: "aapl-20240928.htm"}
+
+	body, err := client.OpenDocument(context.Background(), "320193", filing)
+	require.NoError(t, err)
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	require.NoError(t, err, "a body taking longer than the timeout should still be read")
+	assert.Equal(t, strings.Repeat("<p>Human Capital</p>", 4), string(content))
+
+	// Waiting for the response to start is still timed out
+	filing.PrimaryDocument = "slow-headers.htm"
+	_, err = client.OpenDocument(context.Background(), "320193", filing)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
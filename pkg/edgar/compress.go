@@ -0,0 +1,76 @@
+package edgar
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is sent with every request. Setting it ourselves turns off
+// net/http's transparent gzip handling, so decodeBody takes care of both.
+const acceptEncoding = "gzip, deflate"
+
+// decodedBody closes both the decompressor and the underlying response body
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (d *decodedBody) Close() error {
+	var err error
+	for _, c := range d.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// decodeBody replaces a response's body with one that decompresses it
+// according to its Content-Encoding, so that callers always read plain bytes.
+func decodeBody(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip response: %w", err)
+		}
+		resp.Body = &decodedBody{Reader: gz, closers: []io.Closer{gz, resp.Body}}
+	case "deflate":
+		// "deflate" is meant to be zlib-wrapped, but some servers send raw
+		// DEFLATE data, so check for a zlib header before picking a reader
+		br := bufio.NewReader(resp.Body)
+		var r io.ReadCloser
+		if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return fmt.Errorf("failed to read deflate response: %w", err)
+			}
+			r = zr
+		} else {
+			r = flate.NewReader(br)
+		}
+		resp.Body = &decodedBody{Reader: r, closers: []io.Closer{r, resp.Body}}
+	default:
+		return fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	// The decompressed length isn't known ahead of time
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
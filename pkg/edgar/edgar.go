@@ -1,33 +1,17 @@
 package edgar
 
 import (
-	_ "embed"
-	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
-//go:embed tickers.json
-var tickersJSON []byte
-
-type TickerData struct {
-	CIKStr int    `json:"cik_str"`
-	Ticker string `json:"ticker"`
-	Title  string `json:"title"`
-}
-
-var TickersData map[string]TickerData
-
-func init() {
-	if err := json.Unmarshal(tickersJSON, &TickersData); err != nil {
-		panic(fmt.Sprintf("failed to parse tickers data: %v", err))
-	}
-}
-
 type Document struct {
 	Filing
 	DocumentFile []byte
+	// LabelLinkbase and CalculationLinkbase are the filing's XBRL
+	// linkbases, where they have been loaded with LoadLinkbases
+	LabelLinkbase       []byte
+	CalculationLinkbase []byte
 }
 
 type Filing struct {
@@ -41,27 +25,86 @@ type Filing struct {
 	IsInlineXBLR          int    `json:"isInlineXBRL"`
 	PrimaryDocument       string `json:"primaryDocument"`
 	PrimaryDocDescription string `json:"primaryDocDescription"`
+	// Items lists the item codes reported by an 8-K, e.g. "2.05,9.01"
+	Items                 string `json:"items,omitempty"`
 }
 
 func (f Filing) URL() string {
+	return f.DocumentURL(f.PrimaryDocument)
+}
+
+// DocumentURL returns the URL of a named file within the filing's directory
+func (f Filing) DocumentURL(name string) string {
+	return "https://www.sec.gov" + f.archivePath(name)
+}
+
+// archivePath returns the path of a named file within the filing's
+// directory, relative to the root of the SEC's website
+func (f Filing) archivePath(name string) string {
 	accessionNumber := strings.ReplaceAll(f.AccessionNumber, "-", "")
-	
-	return fmt.Sprintf("https://www.sec.gov/Archives/edgar/data/%s/%s/%s", 
-		f.CIK, accessionNumber, f.PrimaryDocument)
+
+	return fmt.Sprintf("/Archives/edgar/data/%s/%s/%s",
+		f.CIK, accessionNumber, name)
+}
+
+// FilingColumns holds filing metadata in the column-oriented layout used
+// by the submissions API: the fields of the i-th filing are found at
+// index i of each slice.
+type FilingColumns struct {
+	AccessionNumber       []string `json:"accessionNumber"`
+	FilingDate            []string `json:"filingDate"`
+	ReportDate            []string `json:"reportDate"`
+	Form                  []string `json:"form"`
+	FileNumber            []string `json:"fileNumber"`
+	IsXBRL                []int    `json:"isXBRL"`
+	IsInlineXBLR          []int    `json:"isInlineXBRL"`
+	PrimaryDocument       []string `json:"primaryDocument"`
+	PrimaryDocDescription []string `json:"primaryDocDescription"`
+	Items                 []string `json:"items,omitempty"`
+}
+
+// SubmissionsFile describes an additional page of older filings, which the
+// SEC splits out of the main submissions file for long-lived filers.
+type SubmissionsFile struct {
+	Name        string `json:"name"`
+	FilingCount int    `json:"filingCount"`
+	FilingFrom  string `json:"filingFrom"`
+	FilingTo    string `json:"filingTo"`
 }
 
 type Filings struct {
-	Recent struct {
-		AccessionNumber       []string `json:"accessionNumber"`
-		FilingDate            []string `json:"filingDate"`
-		ReportDate            []string `json:"reportDate"`
-		Form                  []string `json:"form"`
-		FileNumber            []string `json:"fileNumber"`
-		IsXBRL                []int    `json:"isXBRL"`
-		IsInlineXBLR          []int    `json:"isInlineXBRL"`
-		PrimaryDocument       []string `json:"primaryDocument"`
-		PrimaryDocDescription []string `json:"primaryDocDescription"`
-	} `json:"recent"`
+	Recent FilingColumns     `json:"recent"`
+	Files  []SubmissionsFile `json:"files,omitempty"`
+}
+
+// Merge appends the filings from an older submissions page to Recent, so
+// that searches cover a filer's complete history, newest first.
+func (f *Filings) Merge(page FilingColumns) {
+	n := len(page.AccessionNumber)
+	f.Recent.AccessionNumber = appendColumn(f.Recent.AccessionNumber, page.AccessionNumber, n)
+	f.Recent.FilingDate = appendColumn(f.Recent.FilingDate, page.FilingDate, n)
+	f.Recent.ReportDate = appendColumn(f.Recent.ReportDate, page.ReportDate, n)
+	f.Recent.Form = appendColumn(f.Recent.Form, page.Form, n)
+	f.Recent.FileNumber = appendColumn(f.Recent.FileNumber, page.FileNumber, n)
+	f.Recent.IsXBRL = appendColumn(f.Recent.IsXBRL, page.IsXBRL, n)
+	f.Recent.IsInlineXBLR = appendColumn(f.Recent.IsInlineXBLR, page.IsInlineXBLR, n)
+	f.Recent.PrimaryDocument = appendColumn(f.Recent.PrimaryDocument, page.PrimaryDocument, n)
+	f.Recent.PrimaryDocDescription = appendColumn(f.Recent.PrimaryDocDescription, page.PrimaryDocDescription, n)
+	f.Recent.Items = appendColumn(f.Recent.Items, page.Items, n)
+}
+
+// appendColumn appends exactly n values from src to dst, padding with zero
+// values if src is short, so every column stays aligned with the others.
+func appendColumn[T any](dst, src []T, n int) []T {
+	if len(src) > n {
+		src = src[:n]
+	}
+	dst = append(dst, src...)
+	for i := len(src); i < n; i++ {
+		var zero T
+		dst = append(dst, zero)
+	}
+	return dst
 }
 
 func (f Filings) Index(i int) Filing {
@@ -75,9 +118,20 @@ func (f Filings) Index(i int) Filing {
 		IsInlineXBLR         : f.Recent.IsInlineXBLR[i],
 		PrimaryDocument      : f.Recent.PrimaryDocument[i],
 		PrimaryDocDescription: f.Recent.PrimaryDocDescription[i],
+		// Submissions cached before items were recorded won't have them
+		Items                : columnValue(f.Recent.Items, i),
 	}
 }
 
+// columnValue returns the i-th value of a column that may be missing
+func columnValue[T any](column []T, i int) T {
+	var zero T
+	if i >= len(column) {
+		return zero
+	}
+	return column[i]
+}
+
 func (f Filings) Search(cik, formName string) (Filing, bool) {
 	for i, name := range f.Recent.Form {
 		if strings.Contains(name, formName) {
@@ -89,45 +143,47 @@ func (f Filings) Search(cik, formName string) (Filing, bool) {
 	return Filing{}, false
 }
 
-type Submissions struct {
-	CIK       string   `json:"cik"`
-	Name      string   `json:"name"`
-	Tickers   []string `json:"tickers"`
-	Exchanges []string `json:"exchanges"`
-	Filings   Filings  `json:"filings"`
-}
-
-// Ticker2CIK returns the CIK string for a given ticker symbol
-func Ticker2CIK(ticker string) (string, error) {
-	// Search for the ticker symbol in the pre-parsed data
-	for _, data := range TickersData {
-		if data.Ticker == ticker {
-			// Convert CIK to string
-			return strconv.Itoa(data.CIKStr), nil
-		}
-	}
-	return "", fmt.Errorf("ticker %s not found", ticker)
+// SearchN returns up to n of the most recent filings of the form
+// formName, newest first. A non-positive n returns every matching filing.
+// Forms must match exactly, so that searching for "10-K" skips 10-K/A
+// amendments, which may only add Part III, and NT 10-K late filing
+// notices, neither of which has financial statements. SearchFunc can be
+// used to include them.
+func (f Filings) SearchN(cik, formName string, n int) []Filing {
+	return f.SearchFunc(cik, n, func(filing Filing) bool {
+		return filing.Form == formName
+	})
 }
 
-func CIK2Ticker(cik string) (string, error) {
-	// Search for the ticker symbol in the pre-parsed data
-	for _, data := range TickersData {
-		cikStr := strconv.Itoa(data.CIKStr)
-		if cik == cikStr {
-			// Convert CIK to string
-			return data.Ticker, nil
+// SearchFunc returns up to n of the most recent filings for which match
+// returns true, newest first. A non-positive n returns every match.
+func (f Filings) SearchFunc(cik string, n int, match func(Filing) bool) []Filing {
+	var filings []Filing
+	for i := range f.Recent.Form {
+		if n > 0 && len(filings) >= n {
+			break
+		}
+		filing := f.Index(i)
+		filing.CIK = cik
+		if match(filing) {
+			filings = append(filings, filing)
 		}
 	}
-	return "", fmt.Errorf("ticker %v not found", cik)
+	return filings
 }
 
-// Ticker2CompanyName returns the company title for a given ticker symbol
-func Ticker2CompanyName(ticker string) (string, error) {
-	// Search for the ticker symbol in the pre-parsed data
-	for _, data := range TickersData {
-		if data.Ticker == ticker {
-			return data.Title, nil
-		}
-	}
-	return "", fmt.Errorf("ticker %s not found", ticker)
+type Submissions struct {
+	CIK         string       `json:"cik"`
+	Name        string       `json:"name"`
+	Tickers     []string     `json:"tickers"`
+	Exchanges   []string     `json:"exchanges"`
+	FormerNames []FormerName `json:"formerNames,omitempty"`
+	Filings     Filings      `json:"filings"`
+}
+
+// FormerName is a name a filer previously used, and the dates it was in use
+type FormerName struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
 }
@@ -0,0 +1,43 @@
+package edgar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filingColumns lays out filings of the given forms, newest first, as
+// the submissions API does
+func filingColumns(forms ...string) FilingColumns {
+	var columns FilingColumns
+	for i, form := range forms {
+		columns.AccessionNumber = append(columns.AccessionNumber, fmt.Sprintf("0000320193-24-%06d", i))
+		columns.FilingDate = append(columns.FilingDate, "2024-01-01")
+		columns.ReportDate = append(columns.ReportDate, "2023-12-31")
+		columns.Form = append(columns.Form, form)
+		columns.FileNumber = append(columns.FileNumber, "001-36743")
+		columns.IsXBRL = append(columns.IsXBRL, 1)
+		columns.IsInlineXBLR = append(columns.IsInlineXBLR, 1)
+		columns.PrimaryDocument = append(columns.PrimaryDocument, "doc.htm")
+		columns.PrimaryDocDescription = append(columns.PrimaryDocDescription, form)
+	}
+	return columns
+}
+
+func TestSearchN(t *testing.T) {
+	filings := Filings{Recent: filingColumns("10-K", "10-K/A", "NT 10-K", "10-KT", "10-Q/A", "10-K", "10-Q")}
+
+	annual := filings.SearchN("320193", "10-K", 0)
+	require.Len(t, annual, 2, "amendments and late filing notices should be skipped")
+	assert.Equal(t, "0000320193-24-000000", annual[0].AccessionNumber)
+	assert.Equal(t, "0000320193-24-000005", annual[1].AccessionNumber)
+	assert.Equal(t, "320193", annual[1].CIK)
+
+	require.Len(t, filings.SearchN("320193", "10-K", 1), 1)
+
+	quarterly := filings.SearchN("320193", "10-Q", 0)
+	require.Len(t, quarterly, 1)
+	assert.Equal(t, "10-Q", quarterly[0].Form)
+}
@@ -0,0 +1,202 @@
+package edgar
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Transaction codes used in Form 4 ownership filings; see the SEC's
+// "General Instructions" for Form 4 for the full list.
+const (
+	TransactionCodePurchase = "P" // open market or private purchase
+	TransactionCodeSale     = "S" // open market or private sale
+	TransactionCodeAward    = "A" // grant or award from the company
+	TransactionCodeExercise = "M" // exercise or conversion of a derivative
+	TransactionCodeTax      = "F" // shares withheld to pay an exercise price or tax
+	TransactionCodeGift     = "G" // bona fide gift
+)
+
+// OwnershipDocument is a Form 3, 4 or 5 filed by an insider, such as an
+// officer or director, reporting their holdings of the issuer's securities.
+type OwnershipDocument struct {
+	DocumentType    string           `xml:"documentType"`
+	PeriodOfReport  string           `xml:"periodOfReport"`
+	Issuer          Issuer           `xml:"issuer"`
+	ReportingOwners []ReportingOwner `xml:"reportingOwner"`
+	Transactions    []Transaction    `xml:"nonDerivativeTable>nonDerivativeTransaction"`
+}
+
+// Issuer is the company whose securities are being reported.
+type Issuer struct {
+	CIK           string `xml:"issuerCik"`
+	Name          string `xml:"issuerName"`
+	TradingSymbol string `xml:"issuerTradingSymbol"`
+}
+
+// ReportingOwner is the insider filing the report, and their relationship
+// to the issuer.
+type ReportingOwner struct {
+	CIK          string
+	Name         string
+	IsDirector   bool
+	IsOfficer    bool
+	IsTenPercent bool
+	OfficerTitle string
+}
+
+// reportingOwnerXML is the layout of a reportingOwner element, whose
+// relationship flags are given as "1"/"0" or "true"/"false".
+type reportingOwnerXML struct {
+	CIK          string `xml:"reportingOwnerId>rptOwnerCik"`
+	Name         string `xml:"reportingOwnerId>rptOwnerName"`
+	IsDirector   string `xml:"reportingOwnerRelationship>isDirector"`
+	IsOfficer    string `xml:"reportingOwnerRelationship>isOfficer"`
+	IsTenPercent string `xml:"reportingOwnerRelationship>isTenPercentOwner"`
+	OfficerTitle string `xml:"reportingOwnerRelationship>officerTitle"`
+}
+
+func (o *ReportingOwner) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw reportingOwnerXML
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	*o = ReportingOwner{
+		CIK:          strings.TrimSpace(raw.CIK),
+		Name:         strings.TrimSpace(raw.Name),
+		IsDirector:   parseFlag(raw.IsDirector),
+		IsOfficer:    parseFlag(raw.IsOfficer),
+		IsTenPercent: parseFlag(raw.IsTenPercent),
+		OfficerTitle: strings.TrimSpace(raw.OfficerTitle),
+	}
+	return nil
+}
+
+// Title describes the owner's relationship to the issuer, e.g.
+// "Chief Executive Officer" or "Director".
+func (o ReportingOwner) Title() string {
+	switch {
+	case o.OfficerTitle != "":
+		return o.OfficerTitle
+	case o.IsDirector:
+		return "Director"
+	case o.IsTenPercent:
+		return "10% Owner"
+	case o.IsOfficer:
+		return "Officer"
+	}
+	return ""
+}
+
+// Transaction is a single purchase, sale, award or other change in an
+// insider's holdings of non-derivative securities, i.e. common stock.
+type Transaction struct {
+	SecurityTitle    string
+	Date             string
+	Code             string
+	Shares           float64
+	PricePerShare    float64
+	AcquiredDisposed string
+	SharesOwnedAfter float64
+}
+
+// transactionXML is the layout of a nonDerivativeTransaction element.
+// Amounts may be empty, e.g. when a price is only given in a footnote.
+type transactionXML struct {
+	SecurityTitle    string `xml:"securityTitle>value"`
+	Date             string `xml:"transactionDate>value"`
+	Code             string `xml:"transactionCoding>transactionCode"`
+	Shares           string `xml:"transactionAmounts>transactionShares>value"`
+	PricePerShare    string `xml:"transactionAmounts>transactionPricePerShare>value"`
+	AcquiredDisposed string `xml:"transactionAmounts>transactionAcquiredDisposedCode>value"`
+	SharesOwnedAfter string `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction>value"`
+}
+
+func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw transactionXML
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	*t = Transaction{
+		SecurityTitle:    strings.TrimSpace(raw.SecurityTitle),
+		Date:             strings.TrimSpace(raw.Date),
+		Code:             strings.TrimSpace(raw.Code),
+		AcquiredDisposed: strings.TrimSpace(raw.AcquiredDisposed),
+	}
+	var err error
+	if t.Shares, err = parseAmount(raw.Shares); err != nil {
+		return err
+	}
+	if t.PricePerShare, err = parseAmount(raw.PricePerShare); err != nil {
+		return err
+	}
+	if t.SharesOwnedAfter, err = parseAmount(raw.SharesOwnedAfter); err != nil {
+		return err
+	}
+	// Dates may carry a timezone offset, e.g. "2024-05-01-05:00"
+	if len(t.Date) > len("2006-01-02") {
+		t.Date = t.Date[:len("2006-01-02")]
+	}
+	return nil
+}
+
+// IsSale reports whether the transaction is a sale of shares
+func (t Transaction) IsSale() bool {
+	return t.Code == TransactionCodeSale && t.AcquiredDisposed == "D"
+}
+
+// Value is the transaction's total value in dollars, where a price was reported
+func (t Transaction) Value() float64 {
+	return t.Shares * t.PricePerShare
+}
+
+// Sales returns the transactions that are sales of shares
+func (d *OwnershipDocument) Sales() []Transaction {
+	var sales []Transaction
+	for _, t := range d.Transactions {
+		if t.IsSale() {
+			sales = append(sales, t)
+		}
+	}
+	return sales
+}
+
+// ParseOwnershipDocument parses the XML of a Form 3, 4 or 5.
+func ParseOwnershipDocument(data []byte) (*OwnershipDocument, error) {
+	var doc OwnershipDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse ownership document: %w", err)
+	}
+	return &doc, nil
+}
+
+// SourceDocument returns the name of the filing's primary document as
+// filed. For XML forms like Form 4, EDGAR lists the primary document as
+// rendered by an XSL stylesheet, e.g. "xslF345X05/form4.xml", which is
+// HTML; the XML itself is the file of the same name outside that directory.
+func (f Filing) SourceDocument() string {
+	dir, name := path.Split(f.PrimaryDocument)
+	if strings.HasPrefix(dir, "xsl") {
+		return name
+	}
+	return f.PrimaryDocument
+}
+
+func parseFlag(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "1" || strings.EqualFold(s, "true")
+}
+
+func parseAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,84 @@
+package edgar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const form4XML = `<?xml version="1.0"?>
+<ownershipDocument>
+	<schemaVersion>X0508</schemaVersion>
+	<documentType>4</documentType>
+	<periodOfReport>2024-10-01</periodOfReport>
+	<issuer>
+		<issuerCik>0000320193</issuerCik>
+		<issuerName>Apple Inc.</issuerName>
+		<issuerTradingSymbol>AAPL</issuerTradingSymbol>
+	</issuer>
+	<reportingOwner>
+		<reportingOwnerId>
+			<rptOwnerCik>0001214156</rptOwnerCik>
+			<rptOwnerName>COOK TIMOTHY D</rptOwnerName>
+		</reportingOwnerId>
+		<reportingOwnerRelationship>
+			<isDirector>true</isDirector>
+			<isOfficer>1</isOfficer>
+			<officerTitle>Chief Executive Officer</officerTitle>
+		</reportingOwnerRelationship>
+	</reportingOwner>
+	<nonDerivativeTable>
+		<nonDerivativeTransaction>
+			<securityTitle><value>Common Stock</value></securityTitle>
+			<transactionDate><value>2024-10-01</value></transactionDate>
+			<transactionCoding><transactionFormType>4</transactionFormType><transactionCode>M</transactionCode></transactionCoding>
+			<transactionAmounts>
+				<transactionShares><value>511000</value></transactionShares>
+				<transactionPricePerShare><footnoteId id="F1"/></transactionPricePerShare>
+				<transactionAcquiredDisposedCode><value>A</value></transactionAcquiredDisposedCode>
+			</transactionAmounts>
+			<postTransactionAmounts><sharesOwnedFollowingTransaction><value>3791745</value></sharesOwnedFollowingTransaction></postTransactionAmounts>
+		</nonDerivativeTransaction>
+		<nonDerivativeTransaction>
+			<securityTitle><value>Common Stock</value></securityTitle>
+			<transactionDate><value>2024-10-02-05:00</value></transactionDate>
+			<transactionCoding><transactionFormType>4</transactionFormType><transactionCode>S</transactionCode></transactionCoding>
+			<transactionAmounts>
+				<transactionShares><value> 223986 </value></transactionShares>
+				<transactionPricePerShare><value>224.5</value></transactionPricePerShare>
+				<transactionAcquiredDisposedCode><value>D</value></transactionAcquiredDisposedCode>
+			</transactionAmounts>
+			<postTransactionAmounts><sharesOwnedFollowingTransaction><value>3280180</value></sharesOwnedFollowingTransaction></postTransactionAmounts>
+		</nonDerivativeTransaction>
+	</nonDerivativeTable>
+</ownershipDocument>`
+
+func TestParseOwnershipDocument(t *testing.T) {
+	doc, err := ParseOwnershipDocument([]byte(form4XML))
+	require.NoError(t, err)
+
+	assert.Equal(t, "4", doc.DocumentType)
+	assert.Equal(t, "AAPL", doc.Issuer.TradingSymbol)
+	require.Len(t, doc.ReportingOwners, 1)
+	owner := doc.ReportingOwners[0]
+	assert.Equal(t, "COOK TIMOTHY D", owner.Name)
+	assert.True(t, owner.IsDirector)
+	assert.True(t, owner.IsOfficer)
+	assert.Equal(t, "Chief Executive Officer", owner.Title())
+
+	require.Len(t, doc.Transactions, 2)
+	assert.Zero(t, doc.Transactions[0].PricePerShare)
+
+	sales := doc.Sales()
+	require.Len(t, sales, 1)
+	assert.Equal(t, "2024-10-02", sales[0].Date)
+	assert.Equal(t, 223986.0, sales[0].Shares)
+	assert.Equal(t, 223986*224.5, sales[0].Value())
+	assert.Equal(t, 3280180.0, sales[0].SharesOwnedAfter)
+}
+
+func TestSourceDocument(t *testing.T) {
+	assert.Equal(t, "wk-form4_1727908205.xml", Filing{PrimaryDocument: "xslF345X05/wk-form4_1727908205.xml"}.SourceDocument())
+	assert.Equal(t, "aapl-20240928.htm", Filing{PrimaryDocument: "aapl-20240928.htm"}.SourceDocument())
+}
@@ -0,0 +1,95 @@
+package edgar
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Item codes reported by 8-K current reports.
+const (
+	Item8KEntryIntoAgreement  = "1.01"
+	Item8KBankruptcy          = "1.03"
+	Item8KResultsOfOperations = "2.02"
+	Item8KRestructuring       = "2.05"
+	Item8KImpairments         = "2.06"
+	Item8KOfficerChanges      = "5.02"
+	Item8KShareholderVote     = "5.07"
+	Item8KRegulationFD        = "7.01"
+	Item8KOtherEvents         = "8.01"
+	Item8KFinancialStatements = "9.01"
+)
+
+// Item8KDescriptions are the captions of the 8-K items this package knows about
+var Item8KDescriptions = map[string]string{
+	Item8KEntryIntoAgreement:  "Entry into a Material Definitive Agreement",
+	Item8KBankruptcy:          "Bankruptcy or Receivership",
+	Item8KResultsOfOperations: "Results of Operations and Financial Condition",
+	Item8KRestructuring:       "Costs Associated with Exit or Disposal Activities",
+	Item8KImpairments:         "Material Impairments",
+	Item8KOfficerChanges:      "Departure of Directors or Certain Officers; Election of Directors; Appointment of Certain Officers",
+	Item8KShareholderVote:     "Submission of Matters to a Vote of Security Holders",
+	Item8KRegulationFD:        "Regulation FD Disclosure",
+	Item8KOtherEvents:         "Other Events",
+	Item8KFinancialStatements: "Financial Statements and Exhibits",
+}
+
+// ItemCodes returns the 8-K item codes listed for the filing in its
+// submissions metadata.
+func (f Filing) ItemCodes() []string {
+	var codes []string
+	for _, code := range strings.Split(f.Items, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// HasItem reports whether the filing reports any of the given 8-K items
+func (f Filing) HasItem(codes ...string) bool {
+	for _, code := range f.ItemCodes() {
+		if slices.Contains(codes, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemHeadingRegex matches item headings, which start a line of text, but
+// not references to items within a sentence
+var itemHeadingRegex = regexp.MustCompile(`(?im)^[ \t]*item\s+(\d{1,2}\.\d{2})\b`)
+
+// ParseItems finds the item codes in the headings of an 8-K's text, e.g.
+// "Item 2.05", for filings whose metadata doesn't list them. The text
+// should have each block on its own line, as returned by ixbrl.HTMLText.
+// Codes are returned in the order they first appear.
+func ParseItems(text string) []string {
+	var codes []string
+	for _, match := range itemHeadingRegex.FindAllStringSubmatch(text, -1) {
+		code := match[1]
+		// Normalize e.g. "02.05" to "2.05"
+		code = strings.TrimPrefix(code, "0")
+		if !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ItemText returns the text of an 8-K item, from its heading up to the
+// next item's heading, or "" if the item isn't found.
+func ItemText(text, code string) string {
+	matches := itemHeadingRegex.FindAllStringSubmatchIndex(text, -1)
+	for i, m := range matches {
+		if strings.TrimPrefix(text[m[2]:m[3]], "0") != code {
+			continue
+		}
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		return strings.TrimSpace(text[m[0]:end])
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package edgar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemCodes(t *testing.T) {
+	filing := Filing{Form: "8-K", Items: "2.05,5.02, 9.01"}
+	assert.Equal(t, []string{"2.05", "5.02", "9.01"}, filing.ItemCodes())
+	assert.True(t, filing.HasItem(Item8KRestructuring))
+	assert.False(t, filing.HasItem(Item8KResultsOfOperations))
+	assert.Empty(t, Filing{}.ItemCodes())
+}
+
+func TestParseItems(t *testing.T) {
+	text := `Item 2.05 Costs Associated with Exit or Disposal Activities.
+On January 10, the Company committed to a plan to reduce its workforce by approximately 5%, as described in Item 2.05 above.
+ITEM 5.02 Departure of Directors or Certain Officers.
+The Chief Financial Officer will step down.
+Item 9.01 Financial Statements and Exhibits.`
+
+	assert.Equal(t, []string{"2.05", "5.02", "9.01"}, ParseItems(text))
+	assert.Equal(t, "ITEM 5.02 Departure of Directors or Certain Officers.\nThe Chief Financial Officer will step down.", ItemText(text, "5.02"))
+	assert.Empty(t, ItemText(text, "1.01"))
+}
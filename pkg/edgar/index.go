@@ -0,0 +1,127 @@
+package edgar
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DocumentType categorizes the files found in a filing's directory.
+type DocumentType string
+
+const (
+	DocumentTypePrimary              DocumentType = "primary"
+	DocumentTypeExhibit              DocumentType = "exhibit"
+	DocumentTypeXBRLInstance         DocumentType = "xbrl-instance"
+	DocumentTypeSchema               DocumentType = "xbrl-schema"
+	DocumentTypeCalculationLinkbase  DocumentType = "calculation-linkbase"
+	DocumentTypeLabelLinkbase        DocumentType = "label-linkbase"
+	DocumentTypePresentationLinkbase DocumentType = "presentation-linkbase"
+	DocumentTypeDefinitionLinkbase   DocumentType = "definition-linkbase"
+	DocumentTypeFilingSummary        DocumentType = "filing-summary"
+	DocumentTypeReport               DocumentType = "report"
+	DocumentTypeGraphic              DocumentType = "graphic"
+	DocumentTypeCompleteSubmission   DocumentType = "complete-submission"
+	DocumentTypeOther                DocumentType = "other"
+)
+
+// FilingIndex lists the files in a filing's directory on EDGAR, which
+// besides the primary document may hold exhibits, the XBRL instance and
+// linkbases, and the FilingSummary.xml describing the financial reports.
+type FilingIndex struct {
+	Directory string
+	Files     []FilingFile
+}
+
+// FilingFile is a single file within a filing's directory.
+type FilingFile struct {
+	Name         string
+	Type         DocumentType
+	Size         int64
+	LastModified string
+}
+
+// Find returns the files of the given type, in directory order.
+func (idx *FilingIndex) Find(t DocumentType) []FilingFile {
+	var files []FilingFile
+	for _, f := range idx.Files {
+		if f.Type == t {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// filingIndexJSON is the shape of the index.json served for each
+// directory of the EDGAR archives.
+type filingIndexJSON struct {
+	Directory struct {
+		Name string `json:"name"`
+		Item []struct {
+			Name         string `json:"name"`
+			Size         string `json:"size"`
+			LastModified string `json:"last-modified"`
+		} `json:"item"`
+	} `json:"directory"`
+}
+
+func (j filingIndexJSON) index(filing Filing) *FilingIndex {
+	idx := &FilingIndex{Directory: j.Directory.Name}
+	for _, item := range j.Directory.Item {
+		size, _ := strconv.ParseInt(item.Size, 10, 64)
+		idx.Files = append(idx.Files, FilingFile{
+			Name:         item.Name,
+			Type:         classifyFile(item.Name, filing),
+			Size:         size,
+			LastModified: item.LastModified,
+		})
+	}
+	return idx
+}
+
+var (
+	exhibitRegex = regexp.MustCompile(`(?i)ex(hibit)?[-_]?\d`)
+	reportRegex  = regexp.MustCompile(`^R\d+\.htm$`)
+	// instanceRegex matches the names of XBRL instances: those extracted
+	// from inline XBRL documents, e.g. "aapl-20240928_htm.xml", and those
+	// filed before inline XBRL, e.g. "aapl-20170930.xml"
+	instanceRegex = regexp.MustCompile(`(?i)(?:_htm\.xml|^[a-z][a-z0-9]*-\d{8}\.xml)$`)
+)
+
+// classifyFile guesses a file's role from EDGAR's file naming conventions.
+// The primary document is matched by the file it's rendered from, as the
+// index lists e.g. "wk-form4.xml" rather than "xslF345X05/wk-form4.xml".
+func classifyFile(name string, filing Filing) DocumentType {
+	lowered := strings.ToLower(name)
+	ext := path.Ext(lowered)
+	switch {
+	case name == filing.SourceDocument():
+		return DocumentTypePrimary
+	case name == "FilingSummary.xml":
+		return DocumentTypeFilingSummary
+	case strings.HasSuffix(lowered, "_cal.xml"):
+		return DocumentTypeCalculationLinkbase
+	case strings.HasSuffix(lowered, "_lab.xml"):
+		return DocumentTypeLabelLinkbase
+	case strings.HasSuffix(lowered, "_pre.xml"):
+		return DocumentTypePresentationLinkbase
+	case strings.HasSuffix(lowered, "_def.xml"):
+		return DocumentTypeDefinitionLinkbase
+	case ext == ".xsd":
+		return DocumentTypeSchema
+	case instanceRegex.MatchString(name):
+		// Other XML files may be e.g. ownership forms or exhibits
+		return DocumentTypeXBRLInstance
+	case reportRegex.MatchString(name):
+		return DocumentTypeReport
+	case ext == ".jpg" || ext == ".gif" || ext == ".png":
+		return DocumentTypeGraphic
+	case name == filing.AccessionNumber+".txt":
+		return DocumentTypeCompleteSubmission
+	case (ext == ".htm" || ext == ".html" || ext == ".txt" || ext == ".pdf") && exhibitRegex.MatchString(name):
+		return DocumentTypeExhibit
+	default:
+		return DocumentTypeOther
+	}
+}
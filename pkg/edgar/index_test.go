@@ -0,0 +1,76 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFilingIndex(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Archives/edgar/data/320193/000032019324000123/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"directory": {"name": "/Archives/edgar/data/320193/000032019324000123", "item": [
+			{"name": "aapl-20240928.htm", "size": "1000", "last-modified": "2024-11-01 06:01:36"},
+			{"name": "ex10-1.htm", "size": "200"},
+			{"name": "R1.htm", "size": "30"},
+			{"name": "FilingSummary.xml", "size": "40"},
+			{"name": "aapl-20240928_htm.xml", "size": "500"},
+			{"name": "aapl-20240928_cal.xml", "size": "10"},
+			{"name": "aapl-20240928_def.xml", "size": "11"},
+			{"name": "aapl-20240928_lab.xml", "size": "12"},
+			{"name": "aapl-20240928_pre.xml", "size": "13"},
+			{"name": "aapl-20240928.xsd", "size": "14"},
+			{"name": "logo.jpg", "size": "15"},
+			{"name": "0000320193-24-000123.txt", "size": "2000"},
+			{"name": "MetaLinks.json", "size": "16"}
+		]}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClient(WithUserAgent("test (test@example.com)"), WithSiteURL(srv.URL))
+	filing := Filing{AccessionNumber: "0000320193-24-000123", PrimaryDocument: "aapl-20240928.htm"}
+
+	index, err := client.LoadFilingIndex(context.Background(), "320193", filing)
+	require.NoError(t, err)
+	assert.Equal(t, "/Archives/edgar/data/320193/000032019324000123", index.Directory)
+
+	types := map[string]DocumentType{}
+	for _, f := range index.Files {
+		types[f.Name] = f.Type
+	}
+	assert.Equal(t, map[string]DocumentType{
+		"aapl-20240928.htm":        DocumentTypePrimary,
+		"ex10-1.htm":               DocumentTypeExhibit,
+		"R1.htm":                   DocumentTypeReport,
+		"FilingSummary.xml":        DocumentTypeFilingSummary,
+		"aapl-20240928_htm.xml":    DocumentTypeXBRLInstance,
+		"aapl-20240928_cal.xml":    DocumentTypeCalculationLinkbase,
+		"aapl-20240928_def.xml":    DocumentTypeDefinitionLinkbase,
+		"aapl-20240928_lab.xml":    DocumentTypeLabelLinkbase,
+		"aapl-20240928_pre.xml":    DocumentTypePresentationLinkbase,
+		"aapl-20240928.xsd":        DocumentTypeSchema,
+		"logo.jpg":                 DocumentTypeGraphic,
+		"0000320193-24-000123.txt": DocumentTypeCompleteSubmission,
+		"MetaLinks.json":           DocumentTypeOther,
+	}, types)
+
+	assert.Equal(t, int64(1000), index.Files[0].Size)
+	assert.Equal(t, "2024-11-01 06:01:36", index.Files[0].LastModified)
+	require.Len(t, index.Find(DocumentTypeXBRLInstance), 1)
+}
+
+func TestClassifyFileXML(t *testing.T) {
+	// Ownership forms are rendered from their XML by a stylesheet
+	form4 := Filing{AccessionNumber: "0001127602-24-000001", PrimaryDocument: "xslF345X05/wk-form4_1700000000.xml"}
+	assert.Equal(t, DocumentTypePrimary, classifyFile("wk-form4_1700000000.xml", form4))
+	assert.Equal(t, DocumentTypeOther, classifyFile("doc4.xml", form4), "XML that isn't an instance shouldn't be taken for one")
+
+	tenK := Filing{AccessionNumber: "0000320193-17-000070", PrimaryDocument: "a10-k20179302017.htm"}
+	assert.Equal(t, DocumentTypeXBRLInstance, classifyFile("aapl-20170930.xml", tenK))
+	assert.Equal(t, DocumentTypeOther, classifyFile("ex99-1.xml", tenK))
+}
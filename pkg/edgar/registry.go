@@ -0,0 +1,327 @@
+package edgar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed tickers.json
+var tickersJSON []byte
+
+type TickerData struct {
+	CIKStr   int    `json:"cik_str"`
+	Ticker   string `json:"ticker"`
+	Title    string `json:"title"`
+	Exchange string `json:"exchange,omitempty"`
+}
+
+// Registry maps between tickers, CIKs and company names. It starts out
+// from a snapshot of the SEC's company_tickers.json, and can be refreshed
+// with the SEC's current list as companies IPO, rename or delist.
+//
+// Lookups are indexed by ticker, CIK and normalized company name, and
+// can be supplemented with the tickers, exchanges and former names found
+// in a company's submissions.
+type Registry struct {
+	mu          sync.RWMutex
+	tickers     []TickerData
+	submissions map[int]Company
+	companies   map[int]*Company
+	byTicker    map[string]int
+	byName      map[string][]int
+}
+
+// Company is everything the registry knows about a single filer.
+type Company struct {
+	CIK         int
+	Name        string
+	Listings    []Listing
+	FormerNames []string
+}
+
+// Listing is a ticker symbol, and the exchange it trades on if known.
+type Listing struct {
+	Ticker   string
+	Exchange string
+}
+
+// CIKStr returns the company's CIK as a string without leading zeros.
+func (c Company) CIKStr() string {
+	return strconv.Itoa(c.CIK)
+}
+
+// Ticker returns the company's primary ticker, if it has any.
+func (c Company) Ticker() string {
+	if len(c.Listings) == 0 {
+		return ""
+	}
+	return c.Listings[0].Ticker
+}
+
+// DefaultRegistry is loaded from the tickers snapshot embedded in this package
+var DefaultRegistry *Registry
+
+func init() {
+	tickers, err := parseTickers(tickersJSON)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse tickers data: %v", err))
+	}
+	DefaultRegistry = NewRegistry(tickers)
+}
+
+// NewRegistry creates a registry holding the given tickers
+func NewRegistry(tickers []TickerData) *Registry {
+	r := &Registry{
+		tickers:     tickers,
+		submissions: map[int]Company{},
+	}
+	r.reindex()
+	return r
+}
+
+// parseTickers parses the company_tickers.json format, an object keyed by
+// each company's rank in the SEC's list, into a slice in that order.
+func parseTickers(data []byte) ([]TickerData, error) {
+	var byRank map[string]TickerData
+	if err := json.Unmarshal(data, &byRank); err != nil {
+		return nil, err
+	}
+	ranks := make([]int, 0, len(byRank))
+	for key := range byRank {
+		rank, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected key %q", key)
+		}
+		ranks = append(ranks, rank)
+	}
+	slices.Sort(ranks)
+
+	tickers := make([]TickerData, 0, len(ranks))
+	for _, rank := range ranks {
+		tickers = append(tickers, byRank[strconv.Itoa(rank)])
+	}
+	return tickers, nil
+}
+
+// Tickers returns every ticker in the registry
+func (r *Registry) Tickers() []TickerData {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.tickers)
+}
+
+// Replace swaps the registry's contents for a freshly loaded list of tickers
+func (r *Registry) Replace(tickers []TickerData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickers = tickers
+	r.reindex()
+}
+
+// AddSubmissions supplements the registry with the tickers, exchanges and
+// former names found in a company's submissions, which cover share classes
+// and names that company_tickers.json doesn't list.
+func (r *Registry) AddSubmissions(s *Submissions) {
+	cik, err := strconv.Atoi(s.CIK)
+	if err != nil {
+		return
+	}
+	company := Company{CIK: cik, Name: s.Name}
+	for i, ticker := range s.Tickers {
+		listing := Listing{Ticker: strings.ToUpper(ticker)}
+		if i < len(s.Exchanges) {
+			listing.Exchange = s.Exchanges[i]
+		}
+		company.Listings = append(company.Listings, listing)
+	}
+	for _, former := range s.FormerNames {
+		company.FormerNames = append(company.FormerNames, former.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submissions[cik] = company
+	r.applySubmissions(company)
+}
+
+// reindex rebuilds the lookup indexes from the ticker list and any added
+// submissions. Callers must hold the write lock.
+func (r *Registry) reindex() {
+	r.companies = map[int]*Company{}
+	r.byTicker = map[string]int{}
+	r.byName = map[string][]int{}
+
+	for _, t := range r.tickers {
+		r.addListing(t.CIKStr, t.Title, Listing{Ticker: strings.ToUpper(t.Ticker), Exchange: t.Exchange})
+	}
+	for _, s := range r.submissions {
+		r.applySubmissions(s)
+	}
+}
+
+// applySubmissions merges the listings and names from a company's
+// submissions into the indexes. Callers must hold the write lock.
+func (r *Registry) applySubmissions(s Company) {
+	for _, listing := range s.Listings {
+		r.addListing(s.CIK, s.Name, listing)
+	}
+	company := r.company(s.CIK, s.Name)
+	r.indexName(s.CIK, s.Name)
+	for _, name := range s.FormerNames {
+		if !slices.Contains(company.FormerNames, name) {
+			company.FormerNames = append(company.FormerNames, name)
+			r.indexName(s.CIK, name)
+		}
+	}
+}
+
+// company returns the indexed company for a CIK, adding it if necessary.
+func (r *Registry) company(cik int, name string) *Company {
+	company, ok := r.companies[cik]
+	if !ok {
+		company = &Company{CIK: cik, Name: name}
+		r.companies[cik] = company
+		r.indexName(cik, name)
+	}
+	return company
+}
+
+func (r *Registry) addListing(cik int, name string, listing Listing) {
+	company := r.company(cik, name)
+	for i, existing := range company.Listings {
+		if existing.Ticker == listing.Ticker {
+			if existing.Exchange == "" {
+				company.Listings[i].Exchange = listing.Exchange
+			}
+			return
+		}
+	}
+	company.Listings = append(company.Listings, listing)
+	if _, taken := r.byTicker[listing.Ticker]; !taken {
+		r.byTicker[listing.Ticker] = cik
+	}
+}
+
+func (r *Registry) indexName(cik int, name string) {
+	key := normalizeName(name)
+	if key != "" && !slices.Contains(r.byName[key], cik) {
+		r.byName[key] = append(r.byName[key], cik)
+	}
+}
+
+// corporateSuffixes are dropped when normalizing names, so that e.g.
+// "Apple Inc." and "APPLE" can be matched.
+var corporateSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "corp": true, "corporation": true,
+	"co": true, "company": true, "ltd": true, "limited": true,
+	"plc": true, "llc": true, "lp": true, "sa": true, "nv": true,
+}
+
+// normalizeName lowercases a company name and strips punctuation, a
+// leading "the" and trailing corporate suffixes.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	for len(words) > 1 && corporateSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// LookupTicker returns the company with the given ticker symbol
+func (r *Registry) LookupTicker(ticker string) (Company, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cik, ok := r.byTicker[strings.ToUpper(ticker)]
+	if !ok {
+		return Company{}, false
+	}
+	return r.copyCompany(cik), true
+}
+
+// LookupCIK returns the company with the given CIK, with or without leading zeros
+func (r *Registry) LookupCIK(cik string) (Company, bool) {
+	n, err := strconv.Atoi(cik)
+	if err != nil {
+		return Company{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.companies[n]; !ok {
+		return Company{}, false
+	}
+	return r.copyCompany(n), true
+}
+
+// LookupName returns the companies whose current or former name matches
+// name, ignoring case, punctuation and corporate suffixes like "Inc."
+func (r *Registry) LookupName(name string) []Company {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var companies []Company
+	for _, cik := range r.byName[normalizeName(name)] {
+		companies = append(companies, r.copyCompany(cik))
+	}
+	return companies
+}
+
+// copyCompany returns a copy of an indexed company that is safe to hand
+// out after the lock is released. Callers must hold the read lock.
+func (r *Registry) copyCompany(cik int) Company {
+	company := *r.companies[cik]
+	company.Listings = slices.Clone(company.Listings)
+	company.FormerNames = slices.Clone(company.FormerNames)
+	return company
+}
+
+// Ticker2CIK returns the CIK string for a given ticker symbol
+func (r *Registry) Ticker2CIK(ticker string) (string, error) {
+	company, ok := r.LookupTicker(ticker)
+	if !ok {
+		return "", fmt.Errorf("ticker %s not found", ticker)
+	}
+	return company.CIKStr(), nil
+}
+
+// CIK2Ticker returns the primary ticker symbol for a given CIK string
+func (r *Registry) CIK2Ticker(cik string) (string, error) {
+	company, ok := r.LookupCIK(cik)
+	if !ok || company.Ticker() == "" {
+		return "", fmt.Errorf("ticker %v not found", cik)
+	}
+	return company.Ticker(), nil
+}
+
+// Ticker2CompanyName returns the company title for a given ticker symbol
+func (r *Registry) Ticker2CompanyName(ticker string) (string, error) {
+	company, ok := r.LookupTicker(ticker)
+	if !ok {
+		return "", fmt.Errorf("ticker %s not found", ticker)
+	}
+	return company.Name, nil
+}
+
+// Ticker2CIK returns the CIK string for a given ticker symbol, using DefaultRegistry
+func Ticker2CIK(ticker string) (string, error) {
+	return DefaultRegistry.Ticker2CIK(ticker)
+}
+
+// CIK2Ticker returns the ticker symbol for a given CIK string, using DefaultRegistry
+func CIK2Ticker(cik string) (string, error) {
+	return DefaultRegistry.CIK2Ticker(cik)
+}
+
+// Ticker2CompanyName returns the company title for a given ticker symbol, using DefaultRegistry
+func Ticker2CompanyName(ticker string) (string, error) {
+	return DefaultRegistry.Ticker2CompanyName(ticker)
+}
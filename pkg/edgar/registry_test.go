@@ -0,0 +1,50 @@
+package edgar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry([]TickerData{
+		{CIKStr: 1067983, Ticker: "BRK-B", Title: "BERKSHIRE HATHAWAY INC", Exchange: "NYSE"},
+		{CIKStr: 320193, Ticker: "AAPL", Title: "Apple Inc.", Exchange: "Nasdaq"},
+		{CIKStr: 1067983, Ticker: "BRK-A", Title: "BERKSHIRE HATHAWAY INC"},
+	})
+
+	cik, err := registry.Ticker2CIK("aapl")
+	require.NoError(t, err)
+	assert.Equal(t, "320193", cik)
+
+	ticker, err := registry.CIK2Ticker("0001067983")
+	require.NoError(t, err)
+	assert.Equal(t, "BRK-B", ticker, "the first listed ticker is the primary one")
+
+	company, ok := registry.LookupCIK("1067983")
+	require.True(t, ok)
+	assert.Equal(t, []Listing{{"BRK-B", "NYSE"}, {"BRK-A", ""}}, company.Listings)
+
+	_, err = registry.Ticker2CIK("NEWCO")
+	assert.Error(t, err)
+
+	registry.AddSubmissions(&Submissions{
+		CIK:         "320193",
+		Name:        "Apple Inc.",
+		Tickers:     []string{"AAPL"},
+		Exchanges:   []string{"Nasdaq"},
+		FormerNames: []FormerName{{Name: "APPLE COMPUTER INC", From: "1994-01-26", To: "2007-01-04"}},
+	})
+	companies := registry.LookupName("Apple Computer, Inc.")
+	require.Len(t, companies, 1)
+	assert.Equal(t, 320193, companies[0].CIK)
+	assert.Equal(t, []string{"APPLE COMPUTER INC"}, companies[0].FormerNames)
+
+	registry.Replace([]TickerData{{CIKStr: 2000000, Ticker: "NEWCO", Title: "Newco Holdings Corp"}})
+	cik, err = registry.Ticker2CIK("NEWCO")
+	require.NoError(t, err)
+	assert.Equal(t, "2000000", cik)
+	require.Len(t, registry.LookupName("newco holdings"), 1)
+	require.Len(t, registry.LookupName("apple computer"), 1, "names from submissions survive a refresh")
+}
@@ -0,0 +1,147 @@
+package edgar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Errors returned by EdgarClient, so that callers can distinguish between
+// documents that don't exist and problems that may go away if retried later.
+var (
+	ErrNotFound    = errors.New("not found on EDGAR")
+	ErrRateLimited = errors.New("rate limited by EDGAR")
+	ErrUnavailable = errors.New("EDGAR is unavailable")
+)
+
+// StatusError is returned when EDGAR responds with an unsuccessful status
+// code. It wraps one of ErrNotFound, ErrRateLimited or ErrUnavailable
+// where the status code corresponds to one of them.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	// RetryAfter is the delay requested by a Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("SEC returned status %d for %s", e.StatusCode, e.URL)
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// retryable reports whether a request failing with this status is worth retrying
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// get performs a GET request, retrying with exponential backoff and
+// jitter when EDGAR is rate limiting or unavailable, or the request
+// failed with a network error. Compressed responses are decompressed
+// transparently. The caller must close the response body.
+func (c *EdgarClient) get(ctx context.Context, url string) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		// Create HTTP request with context
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// Set User-Agent header, and ask for compressed transfer
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+
+		// Make HTTP request (rate limiting handled by transport)
+		var retryAfter time.Duration
+		resp, err := c.httpClient.Do(req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("failed to fetch %s: %w: %w", url, ErrUnavailable, err)
+		case resp.StatusCode == http.StatusOK:
+			if err := decodeBody(resp); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			return resp, nil
+		default:
+			resp.Body.Close()
+			statusErr := &StatusError{
+				URL:        url,
+				StatusCode: resp.StatusCode,
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			}
+			if !statusErr.retryable() {
+				return nil, statusErr
+			}
+			// Retrying sooner than the server asked would only be refused
+			// again, so a longer wait than we're willing to make is left to
+			// the caller, who can find how long from RetryAfter
+			if statusErr.RetryAfter > c.maxRetryDelay {
+				return nil, statusErr
+			}
+			retryAfter = statusErr.RetryAfter
+			lastErr = statusErr
+		}
+
+		if attempt >= c.maxRetries {
+			return nil, lastErr
+		}
+
+		// Wait before retrying, unless the request is cancelled in the meantime
+		timer := time.NewTimer(c.backoff(attempt, retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns how long to wait before retrying: the server's
+// Retry-After if it sent one, which get has already checked is within
+// maxRetryDelay, otherwise an exponentially growing delay with full
+// jitter, capped at maxRetryDelay.
+func (c *EdgarClient) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	ceiling := c.retryDelay << attempt
+	if ceiling <= 0 || ceiling > c.maxRetryDelay {
+		ceiling = c.maxRetryDelay
+	}
+	return rand.N(ceiling) + 1
+}
+
+// parseRetryAfter parses a Retry-After header, given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
@@ -0,0 +1,128 @@
+package edgar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client that retries quickly, so tests don't wait
+func newTestClient() *EdgarClient {
+	return NewClient(
+		WithUserAgent("test (test@example.com)"),
+		WithRateLimit(100),
+		WithRetryPolicy(4, time.Millisecond, 10*time.Millisecond),
+	)
+}
+
+// flakyServer responds with each of statuses in turn, then with a JSON body
+func flakyServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test (test@example.com)", r.Header.Get("User-Agent"))
+		n := int(requests.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{"cik": "320193", "name": "Apple Inc."}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGetRetriesTransientErrors(t *testing.T) {
+	srv, requests := flakyServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway)
+
+	var submissions Submissions
+	err := newTestClient().getJSON(context.Background(), srv.URL, &submissions)
+	require.NoError(t, err)
+	assert.Equal(t, "Apple Inc.", submissions.Name)
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		expected     error
+		wantRequests int32
+	}{
+		{"not found", http.StatusNotFound, ErrNotFound, 1},
+		{"rate limited", http.StatusTooManyRequests, ErrRateLimited, 5},
+		{"unavailable", http.StatusInternalServerError, ErrUnavailable, 5},
+		{"forbidden", http.StatusForbidden, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := make([]int, 10)
+			for i := range statuses {
+				statuses[i] = tt.status
+			}
+			srv, requests := flakyServer(t, statuses...)
+
+			var submissions Submissions
+			err := newTestClient().getJSON(context.Background(), srv.URL, &submissions)
+			require.Error(t, err)
+			var statusErr *StatusError
+			require.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, tt.status, statusErr.StatusCode)
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+			assert.Equal(t, tt.wantRequests, requests.Load())
+		})
+	}
+}
+
+func TestGetNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	var submissions Submissions
+	err := newTestClient().getJSON(context.Background(), srv.URL, &submissions)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	assert.InDelta(t, time.Minute, parseRetryAfter(date), float64(2*time.Second))
+}
+
+func TestBackoff(t *testing.T) {
+	c := NewEdgarClient("test", 1)
+	for attempt := range 10 {
+		delay := c.backoff(attempt, 0)
+		assert.Positive(t, delay)
+		assert.LessOrEqual(t, delay, c.maxRetryDelay)
+	}
+	assert.Equal(t, 5*time.Second, c.backoff(0, 5*time.Second), "Retry-After is respected")
+}
+
+func TestGetLongRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	var submissions Submissions
+	err := newTestClient().getJSON(context.Background(), srv.URL, &submissions)
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, time.Hour, statusErr.RetryAfter)
+	assert.Equal(t, int32(1), requests.Load(), "a Retry-After beyond the maximum delay shouldn't be retried early")
+}
@@ -0,0 +1,126 @@
+package edgar
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// CompanyFacts is the response of the XBRL companyfacts API: every concept
+// a company has reported across all of its filings, grouped by taxonomy
+// (e.g. "us-gaap" or "dei") and then by concept name.
+type CompanyFacts struct {
+	CIK        int                           `json:"cik"`
+	EntityName string                        `json:"entityName"`
+	Facts      map[string]map[string]Concept `json:"facts"`
+}
+
+// Concept returns the values reported for a qualified concept name such
+// as "us-gaap:NetIncomeLoss".
+func (cf *CompanyFacts) Concept(name string) (Concept, bool) {
+	taxonomy, tag, found := strings.Cut(name, ":")
+	if !found {
+		return Concept{}, false
+	}
+	concept, ok := cf.Facts[taxonomy][tag]
+	return concept, ok
+}
+
+// Concept holds every value reported for a single XBRL concept, keyed
+// by unit of measure (e.g. "USD" or "shares").
+type Concept struct {
+	Label       string                    `json:"label"`
+	Description string                    `json:"description"`
+	Units       map[string][]ConceptValue `json:"units"`
+}
+
+// CompanyConcept is the response of the XBRL companyconcept API: the
+// values for a single concept reported by a single company.
+type CompanyConcept struct {
+	CIK        int    `json:"cik"`
+	Taxonomy   string `json:"taxonomy"`
+	Tag        string `json:"tag"`
+	EntityName string `json:"entityName"`
+	Concept
+}
+
+// ConceptValue is a single reported value of a concept. Durations have
+// both a Start and End date, while instants only have an End date.
+type ConceptValue struct {
+	Start string  `json:"start,omitempty"`
+	End   string  `json:"end"`
+	Val   float64 `json:"val"`
+	Accn  string  `json:"accn"`
+	FY    int     `json:"fy"`
+	FP    string  `json:"fp"`
+	Form  string  `json:"form"`
+	Filed string  `json:"filed"`
+	Frame string  `json:"frame,omitempty"`
+}
+
+// IsInstant reports whether the value was measured at a point in time,
+// such as a balance sheet figure, rather than over a period.
+func (v ConceptValue) IsInstant() bool {
+	return v.Start == ""
+}
+
+// AnnualValues returns the values reported in annual reports for full
+// fiscal years (or, for instants, at fiscal year end), newest first. Each
+// period appears once: where a period was restated by a later filing, the
+// most recently filed value is used.
+func AnnualValues(values []ConceptValue) []ConceptValue {
+	latest := map[string]ConceptValue{}
+	for _, v := range values {
+		if v.FP != "FY" || !isAnnualForm(v.Form) {
+			continue
+		}
+		if !v.IsInstant() && !spansYear(v.Start, v.End) {
+			continue
+		}
+		key := v.Start + "/" + v.End
+		if existing, ok := latest[key]; !ok || v.Filed > existing.Filed {
+			latest[key] = v
+		}
+	}
+
+	var annual []ConceptValue
+	for _, v := range latest {
+		annual = append(annual, v)
+	}
+	sortConceptValues(annual)
+	return annual
+}
+
+func isAnnualForm(form string) bool {
+	switch strings.TrimSuffix(form, "/A") {
+	case "10-K", "10-KT", "20-F", "40-F":
+		return true
+	}
+	return false
+}
+
+// spansYear reports whether a duration covers roughly one fiscal year;
+// 52/53 week fiscal years mean this can't be an exact match.
+func spansYear(start, end string) bool {
+	s, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return false
+	}
+	e, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return false
+	}
+	days := e.Sub(s).Hours() / 24
+	return days > 350 && days < 380
+}
+
+// sortConceptValues sorts values in reverse chronological order of the
+// period they describe.
+func sortConceptValues(values []ConceptValue) {
+	slices.SortFunc(values, func(a, b ConceptValue) int {
+		if c := strings.Compare(b.End, a.End); c != 0 {
+			return c
+		}
+		return strings.Compare(b.Start, a.Start)
+	})
+}
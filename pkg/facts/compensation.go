@@ -0,0 +1,130 @@
+package facts
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/saranrapjs/labor-leverage/pkg/edgar"
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+)
+
+// ExecutiveCompensation is an executive's pay for a single fiscal year,
+// as reported in a proxy statement's Summary Compensation Table. Amounts
+// are in dollars, and columns a company doesn't report are 0.
+type ExecutiveCompensation struct {
+	Filing             edgar.Filing `json:"filing"`
+	Name               string       `json:"name"`
+	Title              string       `json:"title,omitempty"`
+	Year               int          `json:"year"`
+	Salary             float64      `json:"salary"`
+	Bonus              float64      `json:"bonus,omitempty"`
+	StockAwards        float64      `json:"stock_awards,omitempty"`
+	OptionAwards       float64      `json:"option_awards,omitempty"`
+	NonEquityIncentive float64      `json:"non_equity_incentive,omitempty"`
+	PensionChange      float64      `json:"pension_change,omitempty"`
+	OtherCompensation  float64      `json:"other_compensation,omitempty"`
+	Total              float64      `json:"total"`
+	// Source is the table row the executive's pay was read from
+	Source *ixbrl.Provenance `json:"source,omitempty"`
+}
+
+// compensationColumns identifies the Summary Compensation Table's
+// columns by their headers. The order matters, as e.g. "Non-Equity
+// Incentive Plan Compensation" also mentions compensation.
+var compensationColumns = []struct {
+	name  string
+	regex *regexp.Regexp
+	value func(*ExecutiveCompensation) *float64
+}{
+	{"non-equity incentive", regexp.MustCompile(`(?i)non-?\s*equity`), func(c *ExecutiveCompensation) *float64 { return &c.NonEquityIncentive }},
+	{"pension change", regexp.MustCompile(`(?i)pension|deferred\s+compensation`), func(c *ExecutiveCompensation) *float64 { return &c.PensionChange }},
+	{"other", regexp.MustCompile(`(?i)all\s+other`), func(c *ExecutiveCompensation) *float64 { return &c.OtherCompensation }},
+	{"salary", regexp.MustCompile(`(?i)salary`), func(c *ExecutiveCompensation) *float64 { return &c.Salary }},
+	{"bonus", regexp.MustCompile(`(?i)bonus`), func(c *ExecutiveCompensation) *float64 { return &c.Bonus }},
+	{"stock awards", regexp.MustCompile(`(?i)stock\s+awards?`), func(c *ExecutiveCompensation) *float64 { return &c.StockAwards }},
+	{"option awards", regexp.MustCompile(`(?i)option\s+awards?`), func(c *ExecutiveCompensation) *float64 { return &c.OptionAwards }},
+	{"total", regexp.MustCompile(`(?i)\btotal\b`), func(c *ExecutiveCompensation) *float64 { return &c.Total }},
+}
+
+var (
+	nameColumnRegex = regexp.MustCompile(`(?i)name`)
+	yearColumnRegex = regexp.MustCompile(`(?i)\byear\b`)
+	// titleRegex finds where an executive's title starts, for tables that
+	// don't put it on a line of its own
+	titleRegex = regexp.MustCompile(`\b(Chief|President|Executive|Senior|Vice|Former|Chair|Chairman|Chairwoman|General Counsel|Co-Founder|Founder|Treasurer|Secretary|Controller|Principal|Head|Group|Managing|EVP|SVP|CEO|CFO|COO|CTO)\b`)
+)
+
+// parseSummaryCompensationTable reads the rows of a Summary Compensation
+// Table. Tables without name, year, salary and total columns, such as
+// director compensation or option award tables, yield nothing.
+func parseSummaryCompensationTable(filing edgar.Filing, table ixbrl.Table) []ExecutiveCompensation {
+	headers := table.Headers()
+	nameColumn, yearColumn := -1, -1
+	columns := map[int]func(*ExecutiveCompensation) *float64{}
+	found := map[string]bool{}
+	for col, header := range headers {
+		switch {
+		case header == "":
+			continue
+		case nameColumn < 0 && nameColumnRegex.MatchString(header):
+			nameColumn = col
+			continue
+		case yearColumn < 0 && yearColumnRegex.MatchString(header):
+			yearColumn = col
+			continue
+		}
+		for _, c := range compensationColumns {
+			if !found[c.name] && c.regex.MatchString(header) {
+				columns[col] = c.value
+				found[c.name] = true
+				break
+			}
+		}
+	}
+	if nameColumn < 0 || yearColumn < 0 || !found["salary"] || !found["total"] {
+		return nil
+	}
+
+	var rows []ExecutiveCompensation
+	var name, title string
+	for _, row := range table.Body() {
+		// Executives' names are only given in their first row, unless the
+		// cell spans the rows of every year
+		if cell := row[nameColumn]; cell.Text != "" && !cell.Numeric {
+			name, title = executiveNameAndTitle(cell)
+		}
+		year := row[yearColumn]
+		if name == "" || !year.Numeric || year.Value < 1900 {
+			continue
+		}
+
+		c := ExecutiveCompensation{Filing: filing, Name: name, Title: title, Year: int(year.Value)}
+		for col, value := range columns {
+			if row[col].Numeric {
+				*value(&c) = row[col].Value
+			}
+		}
+		if c.Total == 0 && c.Salary == 0 {
+			continue
+		}
+		if year.Node != nil {
+			c.Source = source(filing, "table:summary-compensation", ixbrl.NodeProvenance(year.Node.Parent, ""))
+		}
+		rows = append(rows, c)
+	}
+	return rows
+}
+
+// executiveNameAndTitle splits the text of a Summary Compensation Table's
+// name cell into the executive's name and their title
+func executiveNameAndTitle(cell ixbrl.Cell) (string, string) {
+	lines := cell.Lines()
+	if len(lines) > 1 {
+		return lines[0], strings.Join(lines[1:], " ")
+	}
+	text := cell.Text
+	if loc := titleRegex.FindStringIndex(text); loc != nil && loc[0] > 0 {
+		return strings.TrimRight(strings.TrimSpace(text[:loc[0]]), ","), strings.TrimSpace(text[loc[0]:])
+	}
+	return text, ""
+}
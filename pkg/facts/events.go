@@ -0,0 +1,213 @@
+package facts
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/saranrapjs/labor-leverage/pkg/edgar"
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+)
+
+// Event is a corporate event announced in an 8-K current report, such as
+// a restructuring or the departure of an executive.
+type Event struct {
+	Filing edgar.Filing `json:"filing"`
+	// Date is when the event happened, or else when it was reported
+	Date  string   `json:"date"`
+	Items []string `json:"items"`
+	// Amount is the largest dollar amount given for the event, e.g. the
+	// upper estimate of a restructuring's costs, or 0 if none was given
+	Amount float64 `json:"amount,omitempty"`
+	Text   string  `json:"text,omitempty"`
+	// Source is the sentence giving the amount, or else the item's start
+	Source *ixbrl.Provenance `json:"source,omitempty"`
+}
+
+// InsiderSale is a sale of company stock by an officer, director or major
+// shareholder, as reported on Form 4.
+type InsiderSale struct {
+	Filing edgar.Filing `json:"filing"`
+	Date   string       `json:"date"`
+	// Owner is the reporting owner, or the owners joined by "; " where
+	// the form was filed jointly
+	Owner         string  `json:"owner"`
+	Title         string  `json:"title,omitempty"`
+	Shares        float64 `json:"shares"`
+	PricePerShare float64 `json:"price_per_share,omitempty"`
+	Value         float64 `json:"value,omitempty"`
+	// Source is the transaction in the form's XML
+	Source *ixbrl.Provenance `json:"source,omitempty"`
+}
+
+// maxEventText caps the excerpt kept for each event
+const maxEventText = 2000
+
+// maxSnippet caps the excerpt kept as the source of an event's amount
+const maxSnippet = 500
+
+// isEventFiling reports whether a filing is processed for events rather
+// than for the values found in annual reports and proxy statements.
+func isEventFiling(form string) bool {
+	switch strings.TrimSuffix(form, "/A") {
+	case "8-K", "4":
+		return true
+	}
+	return false
+}
+
+// addEvents records the events reported by an 8-K or Form 4.
+func (f *Facts) addEvents(doc edgar.Document) error {
+	if strings.TrimSuffix(doc.Form, "/A") == "4" {
+		return f.addInsiderSales(doc)
+	}
+	return f.addEightKEvents(doc)
+}
+
+func (f *Facts) addInsiderSales(doc edgar.Document) error {
+	ownership, err := edgar.ParseOwnershipDocument(doc.DocumentFile)
+	if err != nil {
+		return err
+	}
+	for i, t := range ownership.Transactions {
+		if !t.IsSale() {
+			continue
+		}
+		// The XML is linked to rather than the rendered form it's listed as
+		filing := doc.Filing
+		filing.PrimaryDocument = filing.SourceDocument()
+		src := source(filing, "form-4:sale", &ixbrl.Provenance{
+			XPath: fmt.Sprintf("/ownershipDocument/nonDerivativeTable/nonDerivativeTransaction[%d]", i+1),
+		})
+		// A joint filing reports each transaction once for all its owners,
+		// e.g. an officer and the trust holding their shares
+		var owners []string
+		var title string
+		for _, owner := range ownership.ReportingOwners {
+			owners = append(owners, owner.Name)
+			if title == "" {
+				title = owner.Title()
+			}
+		}
+		f.InsiderSales = append(f.InsiderSales, InsiderSale{
+			Filing:        doc.Filing,
+			Date:          t.Date,
+			Owner:         strings.Join(owners, "; "),
+			Title:         title,
+			Shares:        t.Shares,
+			PricePerShare: t.PricePerShare,
+			Value:         t.Value(),
+			Source:        src,
+		})
+	}
+	return nil
+}
+
+func (f *Facts) addEightKEvents(doc edgar.Document) error {
+	_, root, err := ixbrl.Parse(bytes.NewReader(doc.DocumentFile))
+	if err != nil {
+		return err
+	}
+	text := ixbrl.HTMLText(root)
+
+	items := doc.ItemCodes()
+	if len(items) == 0 {
+		items = edgar.ParseItems(text)
+	}
+	date := doc.ReportDate
+	if date == "" {
+		date = doc.FilingDate
+	}
+
+	for _, item := range []struct {
+		code   string
+		events *[]Event
+	}{
+		{edgar.Item8KRestructuring, &f.Layoffs},
+		{edgar.Item8KOfficerChanges, &f.ExecutiveChanges},
+	} {
+		if !slices.Contains(items, item.code) {
+			continue
+		}
+		itemText := edgar.ItemText(text, item.code)
+		event := Event{
+			Filing: doc.Filing,
+			Date:   date,
+			Items:  items,
+			Amount: largestDollarAmount(itemText),
+			Text:   truncate(itemText, maxEventText),
+		}
+		snippet := truncate(itemText, maxSnippet)
+		for _, sentence := range sentences(itemText) {
+			if event.Amount > 0 && largestDollarAmount(sentence) == event.Amount {
+				snippet = sentence
+				break
+			}
+		}
+		event.Source = source(doc.Filing, "text:8-k-item-"+item.code, &ixbrl.Provenance{Snippet: snippet})
+		*item.events = append(*item.events, event)
+	}
+	return nil
+}
+
+var dollarAmountRegex = regexp.MustCompile(`(?i)\$\s?([\d,]+(?:\.\d+)?)(?:\s*(thousand|million|billion)\b)?`)
+
+// largestDollarAmount returns the largest dollar amount in text, reading
+// e.g. "$1.2 billion" and "$45,000,000" alike.
+func largestDollarAmount(text string) float64 {
+	var largest float64
+	for _, match := range dollarAmountRegex.FindAllStringSubmatch(text, -1) {
+		amount, err := parseDollarAmount(match[1], match[2])
+		if err != nil {
+			continue
+		}
+		largest = max(largest, amount)
+	}
+	return largest
+}
+
+// parseDollarAmount reads the number and optional scale word matched by
+// dollarAmountRegex
+func parseDollarAmount(number, scale string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToLower(scale) {
+	case "thousand":
+		amount *= 1e3
+	case "million":
+		amount *= 1e6
+	case "billion":
+		amount *= 1e9
+	}
+	return amount, nil
+}
+
+func truncate(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	// Avoid cutting a multi-byte character in half
+	for n > 0 && !isRuneStart(text[n]) {
+		n--
+	}
+	return text[:n] + "…"
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// sortEvents sorts events and insider sales newest first
+func (f *Facts) sortEvents() {
+	byDate := func(a, b Event) int { return strings.Compare(b.Date, a.Date) }
+	slices.SortStableFunc(f.Layoffs, byDate)
+	slices.SortStableFunc(f.ExecutiveChanges, byDate)
+	slices.SortStableFunc(f.InsiderSales, func(a, b InsiderSale) int {
+		return strings.Compare(b.Date, a.Date)
+	})
+}
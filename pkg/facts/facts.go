@@ -3,7 +3,9 @@ package facts
 import (
 	"bytes"
 	"fmt"
+	"log"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	"github.com/saranrapjs/labor-leverage/pkg/edgar"
 	"github.com/saranrapjs/labor-leverage/pkg/irsform"
 	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+	"golang.org/x/net/html"
 	"golang.org/x/text/message"
 )
 
@@ -26,16 +29,39 @@ type Facts struct {
 	NetIncomeLoss        []*ixbrl.NonFraction `json:"net_revenue,omitempty"`
 	Buybacks             []*ixbrl.NonFraction `json:"buybacks,omitempty"`
 	ExecCompensationHTML []string             `json:"exec_compensation_html,omitempty"`
+	ExecutiveCompensation []ExecutiveCompensation `json:"executive_compensation,omitempty"`
+	PayVersusPerformance []PayVersusPerformance `json:"pay_versus_performance,omitempty"`
+	HumanCapital         *HumanCapital          `json:"human_capital,omitempty"`
 	CEOPayRatio          *CEOPayRatio          `json:"ceo_pay_ratio,omitempty"`
 	Cash                 []*ixbrl.NonFraction `json:"cash,omitempty"`
 	EmployeesCount       int                  `json:"employees_count"`
+	EmployeesCountSource *ixbrl.Provenance    `json:"employees_count_source,omitempty"`
 	TotalRevenue         int                  `json:"total_revenue,omitempty"`
 	TotalExpenses        int                  `json:"total_expenses,omitempty"`
 	NetAssets            *ixbrl.NonFraction   `json:"net_assets,omitempty"`
 	WorkerPay            []*ixbrl.NonFraction   `json:"worker_pay,omitempty"`
+	Revenues             []*ixbrl.NonFraction `json:"revenues,omitempty"`
+	SharesOutstanding    []*ixbrl.NonFraction `json:"shares_outstanding,omitempty"`
+	History              []FilingFacts        `json:"history,omitempty"`
+	Layoffs              []Event              `json:"layoffs,omitempty"`
+	ExecutiveChanges     []Event              `json:"executive_changes,omitempty"`
+	InsiderSales         []InsiderSale        `json:"insider_sales,omitempty"`
 }
 
-// FromEdgar processes Edgar filing documents and extracts Facts data
+// FilingFacts holds the values extracted from a single filing, so that
+// several years of the same form can be compared as a time series.
+type FilingFacts struct {
+	Filing         edgar.Filing `json:"filing"`
+	EmployeesCount int          `json:"employees_count,omitempty"`
+	EmployeesCountSource *ixbrl.Provenance `json:"employees_count_source,omitempty"`
+	CEOPayRatio    *CEOPayRatio `json:"ceo_pay_ratio,omitempty"`
+	HumanCapital   *HumanCapital `json:"human_capital,omitempty"`
+}
+
+// FromEdgar processes Edgar filing documents and extracts Facts data.
+// Documents may include several years of the same form: values are
+// merged into a single time series, and where two filings report the
+// same period the most recently filed one wins.
 func FromEdgar(cik, ticker, companyName string, filingDocs []edgar.Document) (*Facts, error) {
 	facts := &Facts{
 		CIK:         cik,
@@ -43,39 +69,108 @@ func FromEdgar(cik, ticker, companyName string, filingDocs []edgar.Document) (*F
 		CompanyName: companyName,
 	}
 
+	// Process the newest filings first, so that restated values take
+	// precedence over the originally reported ones.
+	filingDocs = slices.Clone(filingDocs)
+	slices.SortStableFunc(filingDocs, func(a, b edgar.Document) int {
+		return strings.Compare(b.FilingDate, a.FilingDate)
+	})
+
+	compensationForms := map[string]bool{}
 	for _, f := range filingDocs {
+		// 8-Ks and Form 4s are lists of events rather than annual figures.
+		// Events are best-effort, so one malformed filing is skipped
+		// rather than losing the company's annual figures.
+		if isEventFiling(f.Form) {
+			if err := facts.addEvents(f); err != nil {
+				log.Printf("failed to extract events from %s %s: %v", f.Form, f.AccessionNumber, err)
+			}
+			continue
+		}
+
+		history := FilingFacts{Filing: f.Filing}
 		r := bytes.NewReader(f.DocumentFile)
-		p, doc, err := ixbrl.Parse(r)
+		parsed, err := ixbrl.ParseDocument(r)
 		if err != nil {
 			return nil, err
 		}
+		doc := parsed.Root
+
+		// Label facts using the filing's own linkbases where they were
+		// loaded, or else the standard labels bundled with ixbrl
+		taxonomy := filingTaxonomy(f)
+		parsed.ApplyTaxonomy(taxonomy)
+
+		// Totals that don't add up to their components point to facts
+		// that were tagged wrongly, so they're logged to be checked
+		for _, c := range taxonomy.CheckCalculations(parsed) {
+			total, _ := c.Parent.ScaledValue()
+			log.Printf("calculation inconsistency in %s %s: %s is %g in context %s but its components sum to %g",
+				f.Form, f.AccessionNumber, c.Parent.Name, total, c.Parent.ContextRef, c.Sum)
+		}
 
-		// Extract stock repurchases
-		stockRepurchase := ixbrl.Search(p, func(f *ixbrl.NonFraction) bool {
-			return f.Name == "us-gaap:StockRepurchasedDuringPeriodValue"
-		})
-		if stockRepurchase != nil {
-			facts.Buybacks = append(facts.Buybacks, stockRepurchase)
+		// Cover page facts fill in what the caller didn't know
+		info := parsed.Info()
+		if facts.CompanyName == "" {
+			facts.CompanyName = info.RegistrantName
+		}
+		if info.SharesOutstanding > 0 && info.SharesOutstandingDate != "" {
+			nf := sharesOutstandingFraction(info)
+			// The total may add up several share classes, so the first is
+			// linked to
+			first := ixbrl.Search(parsed.Nodes, func(nf *ixbrl.NonFraction) bool {
+				return nf.Name == "dei:EntityCommonStockSharesOutstanding"
+			})
+			nf.Source = source(f.Filing, "cover-page:shares-outstanding", parsed.FactProvenance(first))
+			facts.SharesOutstanding = appendNewPeriod(facts.SharesOutstanding, nf)
 		}
 
-		// Extract net revenue
-		netRevenue := ixbrl.Search(p, func(f *ixbrl.NonFraction) bool {
-			return f.Name == "us-gaap:NetIncomeLoss"
-		})
-		if netRevenue != nil {
-			facts.NetIncomeLoss = append(facts.NetIncomeLoss, netRevenue)
+		// Only values for the company as a whole are used, not those broken
+		// down by segment or share class. Concepts are listed in order of
+		// preference: repurchases in the statement of equity are often only
+		// tagged per equity component, so the cash flow statement's total
+		// is used when there's no undimensioned value. Annual reports
+		// also tag prior years' comparatives, so each filing only adds the
+		// values for the fiscal year it reports on.
+		periods := fiscalPeriods(info)
+		for _, series := range []struct {
+			names  []string
+			values *[]*ixbrl.NonFraction
+		}{
+			{[]string{"us-gaap:StockRepurchasedDuringPeriodValue", "us-gaap:PaymentsForRepurchaseOfCommonStock"}, &facts.Buybacks},
+			{[]string{"us-gaap:NetIncomeLoss"}, &facts.NetIncomeLoss},
+			{[]string{"us-gaap:CashCashEquivalentsRestrictedCashAndRestrictedCashEquivalents"}, &facts.Cash},
+		} {
+		names:
+			for _, name := range series.names {
+				for _, period := range periods {
+					if nf := parsed.Undimensioned(name, period); nf != nil {
+						nf.Source = source(f.Filing, "", parsed.FactProvenance(nf))
+						*series.values = appendNewPeriod(*series.values, nf)
+						break names
+					}
+				}
+			}
 		}
 
-		// Extract cash
-		cash := ixbrl.Search(p, func(f *ixbrl.NonFraction) bool {
-			return f.Name == "us-gaap:CashCashEquivalentsRestrictedCashAndRestrictedCashEquivalents"
-		})
-		if cash != nil {
-			facts.Cash = append(facts.Cash, cash)
+		// Extractors search the section their values are reported in, when
+		// the document has one, so as not to match mentions elsewhere
+		sections := parsed.Sections()
+		searchSection := func(id string, predicate func(string) string) []ixbrl.Match {
+			if section, ok := ixbrl.FindSection(sections, id); ok {
+				if matches := section.SearchHTML(predicate); len(matches) > 0 {
+					return matches
+				}
+			}
+			return ixbrl.SearchHTML(doc, predicate)
 		}
 
-		// Extract CEO pay ratio
-		ratios := ixbrl.SearchHTML(doc, func(t string) string {
+		// Proxy statements report several years of pay versus performance,
+		// which older proxies extend back in time
+		payVersusPerformance := facts.addPayVersusPerformance(f.Filing, parsed, sections)
+
+		// Extract CEO pay ratio, keeping the best reading of the disclosure
+		ratios := searchSection(ixbrl.SectionPayRatio, func(t string) string {
 			if strings.Contains(strings.ToLower(t), "ceo pay ratio") {
 				return t
 			}
@@ -84,17 +179,31 @@ func FromEdgar(cik, ticker, companyName string, filingDocs []edgar.Document) (*F
 		for _, m := range ratios {
 			leafText := ixbrl.FindNextLeafNodes(m.Node, 700)
 			if strings.Contains(leafText, "$") && strings.Contains(leafText, "median") {
-				ceoRatio := extractCEOPayRatio(leafText)
-				if facts.CEOPayRatio == nil && ceoRatio.Text != "" {
-					facts.CEOPayRatio = &ceoRatio
+				ceoRatio, ok := extractCEOPayRatio(leafText)
+				if !ok {
+					continue
+				}
+				ceoRatio.Source = source(f.Filing, "text:ceo-pay-ratio", ixbrl.NodeProvenance(m.Node, leafText))
+				if history.CEOPayRatio == nil || ceoRatio.Confidence.rank() > history.CEOPayRatio.Confidence.rank() {
+					history.CEOPayRatio = &ceoRatio
+				}
+				if ceoRatio.Confidence == PayRatioHigh {
 					break
 				}
 			}
 		}
+		// The ratio is for the most recent year of the proxy's tables, and
+		// no other year's pay is compared with it
+		if history.CEOPayRatio != nil && len(payVersusPerformance) > 0 {
+			if year := payVersusPerformance[0]; year.Source == PayVersusPerformanceTagged {
+				tagged := history.CEOPayRatio.withTaggedCEOPay(year.PEOTotalCompensation)
+				history.CEOPayRatio = &tagged
+			}
+		}
 
 		// Extract employee count
 		e := regexp.MustCompile("([\\d]{1}[\\d,]{1,})[^.,%]*employees")
-		employees := ixbrl.SearchHTML(doc, func(t string) string {
+		employees := searchSection(ixbrl.SectionBusiness, func(t string) string {
 			lowered := strings.ToLower(t)
 			if strings.Contains(lowered, "december") {
 				match := e.FindAllStringSubmatch(t, -1)
@@ -108,30 +217,288 @@ func FromEdgar(cik, ticker, companyName string, filingDocs []edgar.Document) (*F
 			return ""
 		})
 		for _, m := range employees {
-			if facts.EmployeesCount == 0 {
-				facts.EmployeesCount = onlyNumber(m.Text)
+			if history.EmployeesCount == 0 {
+				history.EmployeesCount = onlyNumber(m.Text)
+				history.EmployeesCountSource = source(f.Filing, "text:employees", ixbrl.NodeProvenance(m.Node, ""))
+			}
+		}
+
+		// Extract the human capital disclosure under its heading in the
+		// 10-K's Item 1, which is preferred to the employee count above
+		// where it states the company's headcount
+		if strings.HasPrefix(f.Form, "10-K") {
+			nodes := []*html.Node{doc}
+			if section, ok := ixbrl.FindSection(sections, ixbrl.SectionBusiness); ok {
+				nodes = section.Nodes
+			}
+			for _, text := range humanCapitalSubsections(ixbrl.HTMLText(nodes...)) {
+				if history.HumanCapital = extractHumanCapital(f.Filing, text); history.HumanCapital != nil {
+					break
+				}
+			}
+			for _, figure := range history.HumanCapital.figures() {
+				figure.Source = source(f.Filing, "text:human-capital", locateSentence(nodes, figure.Sentence))
+			}
+			if headcount, figure := history.HumanCapital.statedHeadcount(); headcount > 0 {
+				history.EmployeesCount = headcount
+				history.EmployeesCountSource = figure.Source
 			}
 		}
 
 		// Extract executive compensation tables
-		tables := ixbrl.FindTables(doc, func(text string) bool {
+		isCompensationTable := func(text string) bool {
 			return strings.Contains(text, "Name") && strings.Contains(text, "$") && strings.Contains(text, "Salary")
-		})
-		for _, t := range tables {
-			facts.ExecCompensationHTML = append(facts.ExecCompensationHTML, ixbrl.Print(t))
+		}
+		var tables []*html.Node
+		if section, ok := ixbrl.FindSection(sections, ixbrl.SectionSummaryCompensation); ok {
+			tables = section.FindTables(isCompensationTable)
+		}
+		if len(tables) == 0 {
+			tables = ixbrl.FindTables(doc, isCompensationTable)
+		}
+		// Older filings of the same form would only repeat these tables
+		if !compensationForms[f.Form] {
+			// Tables that read as a Summary Compensation Table are kept in
+			// preference to others mentioning salaries, like director pay
+			var summaryTables []*html.Node
+			for _, t := range tables {
+				if rows := parseSummaryCompensationTable(f.Filing, ixbrl.NormalizeTable(t)); len(rows) > 0 {
+					facts.ExecutiveCompensation = append(facts.ExecutiveCompensation, rows...)
+					summaryTables = append(summaryTables, t)
+				}
+			}
+			if len(summaryTables) > 0 {
+				tables = summaryTables
+			}
+			for _, t := range tables {
+				facts.ExecCompensationHTML = append(facts.ExecCompensationHTML, ixbrl.Print(t))
+				compensationForms[f.Form] = true
+			}
+		}
+
+		// The headline values come from the most recent filing that reports them
+		if facts.CEOPayRatio == nil {
+			facts.CEOPayRatio = history.CEOPayRatio
+		}
+		if facts.EmployeesCount == 0 {
+			facts.EmployeesCount = history.EmployeesCount
+			facts.EmployeesCountSource = history.EmployeesCountSource
+		}
+		if facts.HumanCapital == nil {
+			facts.HumanCapital = history.HumanCapital
 		}
 
 		facts.Filings = append(facts.Filings, f.Filing)
+		facts.History = append(facts.History, history)
 	}
 
 	// Sort all NonFraction slices in reverse chronological order
 	sortNonFractionsByDate(facts.NetIncomeLoss)
 	sortNonFractionsByDate(facts.Buybacks)
 	sortNonFractionsByDate(facts.Cash)
+	sortNonFractionsByDate(facts.SharesOutstanding)
+	facts.sortEvents()
 
 	return facts, nil
 }
 
+// companyFactsConcepts lists, for each series filled from the XBRL
+// companyfacts API, the concepts to use in order of preference.
+var companyFactsConcepts = []struct {
+	concepts []string
+	unit     string
+	series   func(f *Facts) *[]*ixbrl.NonFraction
+}{
+	{
+		concepts: []string{"us-gaap:NetIncomeLoss"},
+		unit:     "USD",
+		series:   func(f *Facts) *[]*ixbrl.NonFraction { return &f.NetIncomeLoss },
+	},
+	{
+		concepts: []string{"us-gaap:StockRepurchasedDuringPeriodValue", "us-gaap:PaymentsForRepurchaseOfCommonStock"},
+		unit:     "USD",
+		series:   func(f *Facts) *[]*ixbrl.NonFraction { return &f.Buybacks },
+	},
+	{
+		concepts: []string{"us-gaap:CashCashEquivalentsRestrictedCashAndRestrictedCashEquivalents", "us-gaap:CashAndCashEquivalentsAtCarryingValue"},
+		unit:     "USD",
+		series:   func(f *Facts) *[]*ixbrl.NonFraction { return &f.Cash },
+	},
+	{
+		concepts: []string{"us-gaap:Revenues", "us-gaap:RevenueFromContractWithCustomerExcludingAssessedTax", "us-gaap:SalesRevenueNet"},
+		unit:     "USD",
+		series:   func(f *Facts) *[]*ixbrl.NonFraction { return &f.Revenues },
+	},
+	{
+		concepts: []string{"dei:EntityCommonStockSharesOutstanding", "us-gaap:CommonStockSharesOutstanding"},
+		unit:     "shares",
+		series:   func(f *Facts) *[]*ixbrl.NonFraction { return &f.SharesOutstanding },
+	},
+}
+
+// AddCompanyFacts fills the financial series from the structured XBRL
+// companyfacts data, which covers every fiscal year the company has
+// reported. Companies switch concepts over time, e.g. to
+// RevenueFromContractWithCustomerExcludingAssessedTax after ASC 606, so
+// each period's value comes from the most preferred concept reported for
+// it. Series for which the company reported none of the expected
+// concepts keep the values scraped from filing documents by FromEdgar.
+func (f *Facts) AddCompanyFacts(cf *edgar.CompanyFacts) {
+	if cf == nil {
+		return
+	}
+	for _, c := range companyFactsConcepts {
+		scraped := *c.series(f)
+		var series []*ixbrl.NonFraction
+		for _, name := range c.concepts {
+			concept, ok := cf.Concept(name)
+			if !ok {
+				continue
+			}
+			for _, v := range edgar.AnnualValues(concept.Units[c.unit]) {
+				nf := conceptValueToIxFraction(name, c.unit, v)
+				if concept.Label != "" {
+					nf.Labels = &ixbrl.ConceptLabels{Standard: concept.Label}
+				}
+				nf.Source = f.companyFactSource(nf, v.Accn, scraped)
+				series = appendNewPeriod(series, nf)
+			}
+		}
+		if len(series) > 0 {
+			sortNonFractionsByDate(series)
+			*c.series(f) = series
+		}
+	}
+}
+
+// companyFactSource returns where a companyfacts value was reported. The
+// API only says which filing reported it, so where FromEdgar parsed the
+// same fact from that filing its location is kept, and otherwise the
+// filing's primary document is linked to if the filing is known, or else
+// the filing's directory.
+func (f *Facts) companyFactSource(nf *ixbrl.NonFraction, accession string, scraped []*ixbrl.NonFraction) *ixbrl.Provenance {
+	for _, existing := range scraped {
+		if existing.Source != nil && existing.Source.Accession == accession && existing.Name == nf.Name &&
+			existing.Context != nil && existing.Context.Period.Equal(nf.Context.Period) {
+			return existing.Source
+		}
+	}
+
+	filing := edgar.Filing{CIK: f.CIK, AccessionNumber: accession}
+	for _, known := range f.Filings {
+		if known.AccessionNumber == accession {
+			filing = known
+			if filing.CIK == "" {
+				filing.CIK = f.CIK
+			}
+			break
+		}
+	}
+	return &ixbrl.Provenance{
+		Accession:   accession,
+		DocumentURL: filing.DocumentURL(filing.PrimaryDocument),
+		Extractor:   "companyfacts:" + nf.Name,
+	}
+}
+
+// conceptValueToIxFraction adapts a companyfacts value to the
+// NonFraction type used for values scraped from iXBRL documents.
+func conceptValueToIxFraction(name, unit string, v edgar.ConceptValue) *ixbrl.NonFraction {
+	period := ixbrl.Period{StartDate: v.Start, EndDate: v.End}
+	if v.IsInstant() {
+		period = ixbrl.Period{Instant: v.End}
+	}
+	return &ixbrl.NonFraction{
+		Name:    name,
+		UnitRef: unit,
+		Scale:   "0",
+		Content: strconv.FormatFloat(v.Val, 'f', -1, 64),
+		Context: &ixbrl.Context{
+			Period: period,
+		},
+		Unit: companyFactsUnit(unit),
+	}
+}
+
+// companyFactsUnit converts the unit names used by the companyfacts API,
+// e.g. "USD" or "shares", to the measures used in iXBRL documents.
+func companyFactsUnit(unit string) *ixbrl.Unit {
+	measure := unit
+	switch {
+	case unit == "shares" || unit == "pure":
+		measure = "xbrli:" + unit
+	case len(unit) == 3 && strings.ToUpper(unit) == unit:
+		measure = "iso4217:" + unit
+	}
+	return &ixbrl.Unit{ID: unit, Measure: ixbrl.Measure{Content: measure}}
+}
+
+// fiscalPeriods returns the periods a document's own values are reported
+// for: the whole of its fiscal year for flows like net income, and the
+// year's last day for balances like cash. If the document doesn't tag its
+// period, it returns a nil period, matching the first value of any period.
+func fiscalPeriods(info ixbrl.DocumentInfo) []*ixbrl.Period {
+	if info.Period == nil || info.Period.StartDate == "" || info.Period.EndDate == "" {
+		return []*ixbrl.Period{nil}
+	}
+	return []*ixbrl.Period{info.Period, {Instant: info.Period.EndDate}}
+}
+
+// standardTaxonomy labels facts from filings whose linkbases weren't loaded
+var standardTaxonomy = ixbrl.DefaultTaxonomy()
+
+// filingTaxonomy returns the taxonomy to label a filing's facts with and
+// check its calculations against. Labels and calculation checks are only
+// cosmetic, so malformed linkbases are ignored.
+func filingTaxonomy(doc edgar.Document) *ixbrl.Taxonomy {
+	if doc.LabelLinkbase == nil && doc.CalculationLinkbase == nil {
+		return standardTaxonomy
+	}
+	taxonomy := ixbrl.DefaultTaxonomy()
+	if doc.LabelLinkbase != nil {
+		if err := taxonomy.AddLabelLinkbase(bytes.NewReader(doc.LabelLinkbase)); err != nil {
+			taxonomy = ixbrl.DefaultTaxonomy()
+		}
+	}
+	if doc.CalculationLinkbase != nil {
+		if err := taxonomy.AddCalculationLinkbase(bytes.NewReader(doc.CalculationLinkbase)); err != nil {
+			log.Printf("failed to parse calculation linkbase of %s: %v", doc.AccessionNumber, err)
+			taxonomy.Calculations = nil
+		}
+	}
+	return taxonomy
+}
+
+// sharesOutstandingFraction adapts the total shares outstanding from a
+// document's cover page, which may be reported per class of stock.
+func sharesOutstandingFraction(info ixbrl.DocumentInfo) *ixbrl.NonFraction {
+	return &ixbrl.NonFraction{
+		Name:    "dei:EntityCommonStockSharesOutstanding",
+		UnitRef: "shares",
+		Scale:   "0",
+		Content: strconv.FormatFloat(info.SharesOutstanding, 'f', -1, 64),
+		Context: &ixbrl.Context{
+			Period: ixbrl.Period{Instant: info.SharesOutstandingDate},
+		},
+		Unit:   companyFactsUnit("shares"),
+		Labels: standardTaxonomy.Labels("dei:EntityCommonStockSharesOutstanding"),
+	}
+}
+
+// irsSource records the elements of an IRS return a value was read
+// from, e.g. revenue less expenses. Returns aren't linked to, as the
+// IRS doesn't publish them at a stable URL.
+func irsSource(form string, elements ...string) *ixbrl.Provenance {
+	var paths []string
+	for _, element := range elements {
+		paths = append(paths, "/Return/ReturnData/"+form+"/"+element)
+	}
+	return &ixbrl.Provenance{
+		XPath:     strings.Join(paths, " | "),
+		Extractor: "irs:" + form,
+	}
+}
+
 func valueToIxFraction(val int, start, end string) *ixbrl.NonFraction {
 	return &ixbrl.NonFraction{
 		Scale: "0",
@@ -197,28 +564,38 @@ func FromIRS(returnDoc *irsform.Return) (*Facts, error) {
 		}
 		irs990 := data.IRS990
 		facts.EmployeesCount = irs990.TotalEmployeeCnt
-		facts.NetIncomeLoss = append(facts.NetIncomeLoss, valueToIxFraction(irs990.CYTotalRevenueAmt - irs990.CYTotalExpensesAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt))
+		facts.EmployeesCountSource = irsSource("IRS990", "TotalEmployeeCnt")
+		netIncome := valueToIxFraction(irs990.CYTotalRevenueAmt - irs990.CYTotalExpensesAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt)
+		netIncome.Source = irsSource("IRS990", "CYTotalRevenueAmt", "CYTotalExpensesAmt")
+		facts.NetIncomeLoss = append(facts.NetIncomeLoss, netIncome)
 
 		// facts.TotalRevenue = irs990.CYTotalRevenueAmt
 		// facts.TotalExpenses = irs990.CYTotalExpensesAmt
 		facts.NetAssets = valueToIxFraction(irs990.NetAssetsOrFundBalancesEOYAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt)
+		facts.NetAssets.Source = irsSource("IRS990", "NetAssetsOrFundBalancesEOYAmt")
 
 		// Use principal officer business name if available and ReturnHeader name is empty
 		if facts.CompanyName == "" && irs990.PrincipalOfcrBusinessName != nil && irs990.PrincipalOfcrBusinessName.BusinessNameLine1Txt != "" {
 			facts.CompanyName = irs990.PrincipalOfcrBusinessName.BusinessNameLine1Txt
 		}
 		facts.ExecCompensationHTML = append(facts.ExecCompensationHTML, irsExecComp(irs990.Form990PartVIISectionAGrp))
-		facts.WorkerPay = append(facts.WorkerPay, valueToIxFraction(irs990.CYSalariesCompEmpBnftPaidAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt))
+		workerPay := valueToIxFraction(irs990.CYSalariesCompEmpBnftPaidAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt)
+		workerPay.Source = irsSource("IRS990", "CYSalariesCompEmpBnftPaidAmt")
 		previousYearStart, previousYearEnd := minusOneYear(returnDoc.ReturnHeader.TaxPeriodBeginDt)
-		facts.WorkerPay = append(facts.WorkerPay, valueToIxFraction(irs990.PYSalariesCompEmpBnftPaidAmt, previousYearStart, previousYearEnd))
+		previousWorkerPay := valueToIxFraction(irs990.PYSalariesCompEmpBnftPaidAmt, previousYearStart, previousYearEnd)
+		previousWorkerPay.Source = irsSource("IRS990", "PYSalariesCompEmpBnftPaidAmt")
+		facts.WorkerPay = append(facts.WorkerPay, workerPay, previousWorkerPay)
 	case *irsform.ReturnData990EZ:
 		if data.IRS990EZ == nil {
 			return nil, fmt.Errorf("invalid return data: missing IRS990EZ")
 		}
 		// Cast IRS990EZ from interface{} to the actual type
 		irs990ez := data.IRS990EZ
-		facts.NetIncomeLoss = append(facts.NetIncomeLoss, valueToIxFraction(irs990ez.TotalRevenueAmt - irs990ez.TotalExpensesAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt))
+		netIncome := valueToIxFraction(irs990ez.TotalRevenueAmt - irs990ez.TotalExpensesAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt)
+		netIncome.Source = irsSource("IRS990EZ", "TotalRevenueAmt", "TotalExpensesAmt")
+		facts.NetIncomeLoss = append(facts.NetIncomeLoss, netIncome)
 		facts.NetAssets = valueToIxFraction(irs990ez.NetAssetsOrFundBalancesEOYAmt, returnDoc.ReturnHeader.TaxPeriodBeginDt, returnDoc.ReturnHeader.TaxPeriodEndDt)
+		facts.NetAssets.Source = irsSource("IRS990EZ", "NetAssetsOrFundBalancesEOYAmt")
 	case *irsform.ReturnData990PF:
 		if data.IRS990PF == nil {
 			return nil, fmt.Errorf("invalid return data: missing IRS990PF")
@@ -238,58 +615,15 @@ func ExtractFacts(cik, ticker, companyName string, filingDocs []edgar.Document)
 	return FromEdgar(cik, ticker, companyName, filingDocs)
 }
 
-type CEOPayRatio struct {
-	Text string
-	CEO float64
-	Median float64
-}
-
-// extractCEOPayRatio extracts two dollar amounts from text and formats them as CEO vs median
-func extractCEOPayRatio(text string) CEOPayRatio {
-	// Regex to find dollar amounts (including commas and decimals)
-	dollarRegex := regexp.MustCompile(`\$[\d,]+(?:\.\d{2})?`)
-	matches := dollarRegex.FindAllString(text, -1)
-	
-	if len(matches) < 2 {
-		return CEOPayRatio{Text:text}
-	}
-	
-	var amounts []float64
-	var amountStrs []string
-	
-	// Parse each dollar amount
-	for _, match := range matches {
-		// Remove $ and commas
-		cleanAmount := strings.ReplaceAll(strings.TrimPrefix(match, "$"), ",", "")
-		amount, err := strconv.ParseFloat(cleanAmount, 64)
-		if err != nil {
-			continue
+// appendNewPeriod appends nf unless a value for the same period is
+// already present, e.g. a prior year's figure restated in a later 10-K.
+func appendNewPeriod(nfs []*ixbrl.NonFraction, nf *ixbrl.NonFraction) []*ixbrl.NonFraction {
+	for _, existing := range nfs {
+		if existing.Context != nil && nf.Context != nil && existing.Context.Period == nf.Context.Period {
+			return nfs
 		}
-		amounts = append(amounts, amount)
-		amountStrs = append(amountStrs, match)
 	}
-	
-	if len(amounts) < 2 {
-		return CEOPayRatio{Text:text}
-	}
-	
-	// Find highest and lowest amounts
-	var ceoVal, medianVal float64
-	
-	ceoVal = amounts[0]
-	medianVal = amounts[0]
-
-	for _, amount := range amounts {
-		if amount > ceoVal {
-			ceoVal = amount
-		}
-		if amount < medianVal {
-			medianVal = amount
-		}
-	}
-	
-	// Format the result
-	return CEOPayRatio{text, ceoVal, medianVal}
+	return append(nfs, nf)
 }
 
 // sortNonFractionsByDate sorts a slice of NonFraction in reverse chronological order
@@ -1,6 +1,7 @@
 package facts
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/saranrapjs/labor-leverage/pkg/edgar"
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
 )
 
 func TestExtractCEOPayRatio(t *testing.T) {
@@ -49,7 +51,819 @@ func TestExtractCEOPayRatio(t *testing.T) {
 				require.NotNil(t, facts.CEOPayRatio, "Expected CEOPayRatio to be extracted")
 				assert.Equal(t, tt.expectedCEOPay, facts.CEOPayRatio.CEO, "CEO pay mismatch")
 				assert.Equal(t, tt.expectedMedianPay, facts.CEOPayRatio.Median, "Median pay mismatch")
+				assert.Equal(t, 650.0, facts.CEOPayRatio.Ratio, "Stated ratio mismatch")
+				assert.Equal(t, PayRatioHigh, facts.CEOPayRatio.Confidence)
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestExtractCEOPayRatioText(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		ceo        float64
+		median     float64
+		ratio      float64
+		confidence PayRatioConfidence
+	}{
+		{
+			name:       "median stated first",
+			text:       "The median of the annual total compensation of all employees other than our CEO was $62,000. Our CEO's annual total compensation was $12,400,000, a ratio of 200:1.",
+			ceo:        12400000,
+			median:     62000,
+			ratio:      200,
+			confidence: PayRatioHigh,
+		},
+		{
+			name:       "unrelated amounts nearby",
+			text:       "Employees paid less than $25,000 were excluded, as were 1,200 employees outside the US. Our CEO's total compensation was $5,000,000 and our median employee's was $50,000, or 100 to 1. Our revenues were $2,000,000,000.",
+			ceo:        5000000,
+			median:     50000,
+			ratio:      100,
+			confidence: PayRatioHigh,
+		},
+		{
+			name:       "amounts in millions",
+			text:       "Our chief executive officer was paid $7.5 million, and the median employee $75,000, a pay ratio of 100 to 1.",
+			ceo:        7500000,
+			median:     75000,
+			ratio:      100,
+			confidence: PayRatioHigh,
+		},
+		{
+			name:       "no stated ratio",
+			text:       "The CEO received $3,000,000 while the median employee received $60,000.",
+			ceo:        3000000,
+			median:     60000,
+			ratio:      50,
+			confidence: PayRatioMedium,
+		},
+		{
+			name:       "median derived from ratio",
+			text:       "Our CEO was paid $9,000,000 in total compensation, which is 300 to 1 relative to the median.",
+			ceo:        9000000,
+			median:     30000,
+			ratio:      300,
+			confidence: PayRatioMedium,
+		},
+		{
+			name:       "amounts contradicting the ratio",
+			text:       "Our CEO was paid $9,000,000 and the median employee $30,000, a ratio of 150 to 1.",
+			ceo:        9000000,
+			median:     30000,
+			ratio:      150,
+			confidence: PayRatioLow,
+		},
+		{
+			name:       "amounts without roles",
+			text:       "Total compensation: $4,000,000 and $40,000, for a ratio of 100:1.",
+			ceo:        4000000,
+			median:     40000,
+			ratio:      100,
+			confidence: PayRatioMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratio, ok := extractCEOPayRatio(tt.text)
+			require.True(t, ok)
+			assert.Equal(t, tt.ceo, ratio.CEO, "CEO pay mismatch")
+			assert.InDelta(t, tt.median, ratio.Median, 0.5, "Median pay mismatch")
+			assert.Equal(t, tt.ratio, ratio.Ratio, "Ratio mismatch")
+			assert.Equal(t, tt.confidence, ratio.Confidence)
+		})
+	}
+
+	_, ok := extractCEOPayRatio("Our CEO pay ratio is disclosed below.")
+	assert.False(t, ok)
+}
+
+func TestWithTaggedCEOPay(t *testing.T) {
+	// The tagged amount agrees with the stated median and ratio
+	stated, ok := extractCEOPayRatio("Our CEO was paid $1,000 and the median employee $50,000, a ratio of 200 to 1.")
+	require.True(t, ok)
+	tagged := stated.withTaggedCEOPay(10000000)
+	assert.Equal(t, 10000000.0, tagged.CEO)
+	assert.Equal(t, 50000.0, tagged.Median)
+
+	// A stated median isn't replaced by one derived from a tagged amount
+	// that disagrees with it
+	stated, ok = extractCEOPayRatio("The median employee was paid $50,000, and the ratio is 200 to 1.")
+	require.True(t, ok)
+	tagged = stated.withTaggedCEOPay(8000000)
+	assert.Equal(t, 50000.0, tagged.Median)
+	assert.Equal(t, 10000000.0, tagged.CEO)
+
+	// A median derived from the text's CEO pay is derived again from the
+	// tagged amount
+	derived, ok := extractCEOPayRatio("Our CEO was paid $9,000,000, which is 300 to 1 relative to the median.")
+	require.True(t, ok)
+	tagged = derived.withTaggedCEOPay(6000000)
+	assert.Equal(t, 6000000.0, tagged.CEO)
+	assert.Equal(t, 20000.0, tagged.Median)
+}
+
+// annualReport builds a minimal iXBRL document reporting net income and
+// headcount for the fiscal year ending on end.
+func annualReport(start, end, netIncome, employees string) []byte {
+	return []byte(`<html><body>
+		<xbrli:context id="c-1"><xbrli:period>
+			<xbrli:startDate>` + start + `</xbrli:startDate>
+			<xbrli:endDate>` + end + `</xbrli:endDate>
+		</xbrli:period></xbrli:context>
+		<p>Net income was $<ix:nonfraction unitref="usd" contextref="c-1" name="us-gaap:NetIncomeLoss" scale="6">` + netIncome + `</ix:nonfraction> million.</p>
+		<p>As of December 31, we had ` + employees + ` full-time employees.</p>
+	</body></html>`)
+}
+
+func TestFromEdgarHistory(t *testing.T) {
+	docs := []edgar.Document{
+		{
+			Filing:       edgar.Filing{Form: "10-K", FilingDate: "2023-02-01"},
+			DocumentFile: annualReport("2022-01-01", "2022-12-31", "90", "1,000"),
+		},
+		{
+			Filing:       edgar.Filing{Form: "10-K", FilingDate: "2024-02-01"},
+			DocumentFile: annualReport("2023-01-01", "2023-12-31", "100", "1,200"),
+		},
+		{
+			// An amendment restating the most recent year
+			Filing:       edgar.Filing{Form: "10-K/A", FilingDate: "2024-06-01"},
+			DocumentFile: annualReport("2023-01-01", "2023-12-31", "110", "1,200"),
+		},
+	}
+
+	facts, err := FromEdgar("test-cik", "TEST", "Test Company", docs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1200, facts.EmployeesCount, "headline count should come from the newest filing")
+
+	require.Len(t, facts.History, 3)
+	assert.Equal(t, "2024-06-01", facts.History[0].Filing.FilingDate)
+	assert.Equal(t, "2023-02-01", facts.History[2].Filing.FilingDate)
+	assert.Equal(t, 1000, facts.History[2].EmployeesCount)
+
+	require.Len(t, facts.NetIncomeLoss, 2, "restated periods should not be duplicated")
+	assertScaled(t, 110000000.0, facts.NetIncomeLoss[0])
+	assertScaled(t, 90000000.0, facts.NetIncomeLoss[1])
+}
+
+func TestFromEdgarFiscalYear(t *testing.T) {
+	// The prior year's comparatives come first in the document
+	doc := []byte(`<html><body>
+		<xbrli:context id="c-prior"><xbrli:period>
+			<xbrli:startDate>2023-01-01</xbrli:startDate><xbrli:endDate>2023-12-31</xbrli:endDate>
+		</xbrli:period></xbrli:context>
+		<xbrli:context id="c-prior-end"><xbrli:period><xbrli:instant>2023-12-31</xbrli:instant></xbrli:period></xbrli:context>
+		<xbrli:context id="c-1"><xbrli:period>
+			<xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate>
+		</xbrli:period></xbrli:context>
+		<xbrli:context id="c-end"><xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period></xbrli:context>
+		<p>For the fiscal year ended <ix:nonNumeric name="dei:DocumentPeriodEndDate" contextRef="c-1">2024-12-31</ix:nonNumeric></p>
+		<p>Net income was $<ix:nonfraction unitref="usd" contextref="c-prior" name="us-gaap:NetIncomeLoss">90</ix:nonfraction>
+		and $<ix:nonfraction unitref="usd" contextref="c-1" name="us-gaap:NetIncomeLoss">100</ix:nonfraction>.</p>
+		<p>Cash was $<ix:nonfraction unitref="usd" contextref="c-prior-end" name="us-gaap:CashCashEquivalentsRestrictedCashAndRestrictedCashEquivalents">5</ix:nonfraction>
+		and $<ix:nonfraction unitref="usd" contextref="c-end" name="us-gaap:CashCashEquivalentsRestrictedCashAndRestrictedCashEquivalents">7</ix:nonfraction>.</p>
+	</body></html>`)
+
+	facts, err := FromEdgar("test-cik", "TEST", "Test Company", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2025-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, facts.NetIncomeLoss, 1)
+	assert.Equal(t, "2024-12-31", facts.NetIncomeLoss[0].Context.Period.EndDate)
+	assertScaled(t, 100.0, facts.NetIncomeLoss[0])
+	require.Len(t, facts.Cash, 1)
+	assert.Equal(t, "2024-12-31", facts.Cash[0].Context.Period.Instant)
+	assertScaled(t, 7.0, facts.Cash[0])
+}
+
+func TestAddCompanyFacts(t *testing.T) {
+	const companyFactsJSON = `{
+		"cik": 320193,
+		"entityName": "Test Company",
+		"facts": {
+			"us-gaap": {
+				"NetIncomeLoss": {
+					"label": "Net Income (Loss) Attributable to Parent",
+					"units": {
+						"USD": [
+							{"start": "2022-01-01", "end": "2022-12-31", "val": 90, "accn": "0000000000-23-000001", "fy": 2022, "fp": "FY", "form": "10-K", "filed": "2023-02-01"},
+							{"start": "2023-01-01", "end": "2023-03-31", "val": 20, "accn": "0000000000-23-000002", "fy": 2023, "fp": "Q1", "form": "10-Q", "filed": "2023-05-01"},
+							{"start": "2022-01-01", "end": "2022-12-31", "val": 95, "accn": "0000000000-24-000001", "fy": 2023, "fp": "FY", "form": "10-K", "filed": "2024-02-01"},
+							{"start": "2023-01-01", "end": "2023-12-31", "val": 100, "accn": "0000000000-24-000001", "fy": 2023, "fp": "FY", "form": "10-K", "filed": "2024-02-01"}
+						]
+					}
+				},
+				"SalesRevenueNet": {
+					"units": {
+						"USD": [
+							{"start": "2017-01-01", "end": "2017-12-31", "val": 700, "accn": "0000000000-18-000001", "fy": 2017, "fp": "FY", "form": "10-K", "filed": "2018-02-01"}
+						]
+					}
+				},
+				"RevenueFromContractWithCustomerExcludingAssessedTax": {
+					"units": {
+						"USD": [
+							{"start": "2022-01-01", "end": "2022-12-31", "val": 900, "accn": "0000000000-23-000001", "fy": 2022, "fp": "FY", "form": "10-K", "filed": "2023-02-01"},
+							{"start": "2023-01-01", "end": "2023-12-31", "val": 1000, "accn": "0000000000-24-000001", "fy": 2023, "fp": "FY", "form": "10-K", "filed": "2024-02-01"}
+						]
+					}
+				}
+			},
+			"dei": {
+				"EntityCommonStockSharesOutstanding": {
+					"units": {
+						"shares": [
+							{"end": "2024-01-15", "val": 5000, "accn": "0000000000-24-000001", "fy": 2023, "fp": "FY", "form": "10-K", "filed": "2024-02-01"}
+						]
+					}
+				}
+			}
+		}
+	}`
+	var cf edgar.CompanyFacts
+	require.NoError(t, json.Unmarshal([]byte(companyFactsJSON), &cf))
+
+	scrapedCash := valueToIxFraction(10, "", "2023-12-31")
+	facts := &Facts{Cash: []*ixbrl.NonFraction{scrapedCash}}
+	facts.AddCompanyFacts(&cf)
+
+	require.Len(t, facts.NetIncomeLoss, 2, "quarterly values should be skipped and restatements merged")
+	assert.Equal(t, "2023-12-31", facts.NetIncomeLoss[0].Context.Period.EndDate)
+	assertScaled(t, 100.0, facts.NetIncomeLoss[0])
+	assertScaled(t, 95.0, facts.NetIncomeLoss[1], "restated value should win")
+	assert.Empty(t, facts.NetIncomeLoss[0].ID, "companyfacts values have no fact id")
+
+	require.Len(t, facts.Revenues, 3, "revenue should be merged across the concepts reported over time")
+	assert.Equal(t, "us-gaap:RevenueFromContractWithCustomerExcludingAssessedTax", facts.Revenues[0].Name)
+	assertScaled(t, 1000.0, facts.Revenues[0])
+	assert.Equal(t, "us-gaap:SalesRevenueNet", facts.Revenues[2].Name)
+	assert.Equal(t, "2017-12-31", facts.Revenues[2].Context.Period.EndDate)
+
+	require.Len(t, facts.SharesOutstanding, 1)
+	assert.Equal(t, "2024-01-15", facts.SharesOutstanding[0].Context.Period.Instant)
+	assertScaled(t, 5000.0, facts.SharesOutstanding[0])
+	assert.True(t, facts.SharesOutstanding[0].Unit.IsShares())
+	assert.True(t, facts.NetIncomeLoss[0].Unit.IsMonetary())
+
+	assert.Equal(t, []*ixbrl.NonFraction{scrapedCash}, facts.Cash, "scraped values should be kept when no concept is reported")
+}
+
+func TestFromEdgarEvents(t *testing.T) {
+	eightK := []byte(`<html><body>
+		<p>Item 2.05 Costs Associated with Exit or Disposal Activities.</p>
+		<p>The Company expects to incur charges of between $1.5 million and $2.3 million, primarily severance, as described in Item 2.05.</p>
+		<p>Item 9.01 Financial Statements and Exhibits.</p>
+	</body></html>`)
+	form4 := []byte(`<ownershipDocument>
+		<documentType>4</documentType>
+		<reportingOwner>
+			<reportingOwnerId><rptOwnerName>Doe Jane</rptOwnerName></reportingOwnerId>
+			<reportingOwnerRelationship><isOfficer>1</isOfficer><officerTitle>CFO</officerTitle></reportingOwnerRelationship>
+		</reportingOwner>
+		<nonDerivativeTable><nonDerivativeTransaction>
+			<transactionDate><value>2024-03-04</value></transactionDate>
+			<transactionCoding><transactionCode>S</transactionCode></transactionCoding>
+			<transactionAmounts>
+				<transactionShares><value>1000</value></transactionShares>
+				<transactionPricePerShare><value>50</value></transactionPricePerShare>
+				<transactionAcquiredDisposedCode><value>D</value></transactionAcquiredDisposedCode>
+			</transactionAmounts>
+		</nonDerivativeTransaction></nonDerivativeTable>
+	</ownershipDocument>`)
+	jointForm4 := []byte(`<ownershipDocument>
+		<documentType>4</documentType>
+		<reportingOwner>
+			<reportingOwnerId><rptOwnerName>Doe Jane</rptOwnerName></reportingOwnerId>
+			<reportingOwnerRelationship><isOfficer>1</isOfficer><officerTitle>CFO</officerTitle></reportingOwnerRelationship>
+		</reportingOwner>
+		<reportingOwner>
+			<reportingOwnerId><rptOwnerName>Doe Family Trust</rptOwnerName></reportingOwnerId>
+			<reportingOwnerRelationship><isTenPercentOwner>0</isTenPercentOwner></reportingOwnerRelationship>
+		</reportingOwner>
+		<nonDerivativeTable><nonDerivativeTransaction>
+			<transactionDate><value>2024-02-02</value></transactionDate>
+			<transactionCoding><transactionCode>S</transactionCode></transactionCoding>
+			<transactionAmounts>
+				<transactionShares><value>400</value></transactionShares>
+				<transactionPricePerShare><value>50</value></transactionPricePerShare>
+				<transactionAcquiredDisposedCode><value>D</value></transactionAcquiredDisposedCode>
+			</transactionAmounts>
+		</nonDerivativeTransaction></nonDerivativeTable>
+	</ownershipDocument>`)
+
+	docs := []edgar.Document{
+		{
+			// No items in the metadata, so they are found in the text
+			Filing:       edgar.Filing{Form: "8-K", FilingDate: "2024-01-12", ReportDate: "2024-01-10"},
+			DocumentFile: eightK,
+		},
+		{
+			Filing:       edgar.Filing{Form: "4", FilingDate: "2024-03-05"},
+			DocumentFile: form4,
+		},
+		{
+			// Filed jointly with the trust holding the shares
+			Filing:       edgar.Filing{Form: "4", FilingDate: "2024-02-05"},
+			DocumentFile: jointForm4,
+		},
+		{
+			// A malformed filing loses only its own events
+			Filing:       edgar.Filing{Form: "4", FilingDate: "2024-02-01"},
+			DocumentFile: []byte(`<ownershipDocument><reportingOwner>`),
+		},
+	}
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", docs)
+	require.NoError(t, err)
+
+	require.Len(t, facts.Layoffs, 1)
+	assert.Equal(t, "2024-01-10", facts.Layoffs[0].Date)
+	assert.Equal(t, []string{"2.05", "9.01"}, facts.Layoffs[0].Items)
+	assert.Equal(t, 2.3e6, facts.Layoffs[0].Amount)
+	assert.Contains(t, facts.Layoffs[0].Text, "severance")
+	assert.NotContains(t, facts.Layoffs[0].Text, "Financial Statements")
+	assert.Empty(t, facts.ExecutiveChanges)
+
+	require.Len(t, facts.InsiderSales, 2, "a joint filing's transactions should be counted once")
+	assert.Equal(t, "Doe Jane", facts.InsiderSales[0].Owner)
+	assert.Equal(t, "CFO", facts.InsiderSales[0].Title)
+	assert.Equal(t, 50000.0, facts.InsiderSales[0].Value)
+	assert.Equal(t, "Doe Jane; Doe Family Trust", facts.InsiderSales[1].Owner)
+	assert.Equal(t, "CFO", facts.InsiderSales[1].Title)
+	assert.Equal(t, 20000.0, facts.InsiderSales[1].Value)
+
+	// Events aren't annual figures
+	assert.Empty(t, facts.History)
+}
+
+func assertScaled(t *testing.T, expected float64, nf *ixbrl.NonFraction, msgAndArgs ...interface{}) {
+	t.Helper()
+	value, err := nf.ScaledValue()
+	require.NoError(t, err)
+	assert.Equal(t, expected, value, msgAndArgs...)
+}
+
+func TestFromEdgarIgnoresSegments(t *testing.T) {
+	doc := []byte(`<html><body>
+		<xbrli:context id="c-segment">
+			<xbrli:entity><xbrli:segment>
+				<xbrldi:explicitMember dimension="us-gaap:StatementBusinessSegmentsAxis">test:RetailMember</xbrldi:explicitMember>
+			</xbrli:segment></xbrli:entity>
+			<xbrli:period><xbrli:startDate>2023-01-01</xbrli:startDate><xbrli:endDate>2023-12-31</xbrli:endDate></xbrli:period>
+		</xbrli:context>
+		<xbrli:context id="c-total">
+			<xbrli:period><xbrli:startDate>2023-01-01</xbrli:startDate><xbrli:endDate>2023-12-31</xbrli:endDate></xbrli:period>
+		</xbrli:context>
+		<p>Retail: <ix:nonfraction unitref="usd" contextref="c-segment" name="us-gaap:NetIncomeLoss" scale="6">40</ix:nonfraction></p>
+		<p>Total: <ix:nonfraction unitref="usd" contextref="c-total" name="us-gaap:NetIncomeLoss" scale="6">100</ix:nonfraction></p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2024-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts.NetIncomeLoss, 1)
+	assertScaled(t, 100000000.0, facts.NetIncomeLoss[0])
+}
+
+func TestFromEdgarCoverPage(t *testing.T) {
+	doc := []byte(`<html><body>
+		<div style="display:none"><ix:header><ix:hidden>
+			<ix:nonNumeric name="dei:EntityRegistrantName" contextRef="c-1">Test Corp</ix:nonNumeric>
+		</ix:hidden><ix:resources>
+			<xbrli:context id="c-shares"><xbrli:period><xbrli:instant>2024-01-31</xbrli:instant></xbrli:period></xbrli:context>
+		</ix:resources></ix:header></div>
+		<p><ix:nonFraction name="dei:EntityCommonStockSharesOutstanding" contextRef="c-shares" unitRef="shares">5,000</ix:nonFraction> shares outstanding</p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2024-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Test Corp", facts.CompanyName)
+	require.Len(t, facts.SharesOutstanding, 1)
+	assertScaled(t, 5000.0, facts.SharesOutstanding[0])
+	assert.Equal(t, "2024-01-31", facts.SharesOutstanding[0].Context.Period.Instant)
+}
+
+func TestFromEdgarSearchesSections(t *testing.T) {
+	doc := []byte(`<html><body>
+		<p>Forward-looking statements: by December 31, our customers expect 25,000 employees to use our products.</p>
+		<p><b>Item 1. Business</b></p>
+		<p>As of December 31, we had 1,500 full-time employees.</p>
+		<p><b>Item 1A. Risk Factors</b></p>
+		<p>We may lose key employees.</p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2024-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1500, facts.EmployeesCount, "the employee count should come from Item 1")
+}
+
+func TestFromEdgarLabels(t *testing.T) {
+	doc := annualReport("2024-01-01", "2024-12-31", "100", "1,000")
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2025-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts.NetIncomeLoss, 1)
+	assert.Equal(t, "Net Income (Loss) Attributable to Parent", facts.NetIncomeLoss[0].Label(), "the bundled standard label should be used")
+
+	labels := []byte(`<linkbase xmlns="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+		<labelLink>
+			<loc xlink:href="us-gaap-2024.xsd#us-gaap_NetIncomeLoss" xlink:label="loc"/>
+			<label xlink:label="lab" xlink:role="http://www.xbrl.org/2003/role/terseLabel" xml:lang="en-US">Net income</label>
+			<labelArc xlink:from="loc" xlink:to="lab"/>
+		</labelLink>
+	</linkbase>`)
+	facts, err = FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2025-02-01"}, DocumentFile: doc, LabelLinkbase: labels},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts.NetIncomeLoss, 1)
+	assert.Equal(t, "Net income", facts.NetIncomeLoss[0].Label(), "the filing's own terse label should be preferred")
+}
+
+func TestFilingTaxonomy(t *testing.T) {
+	calculations := []byte(`<linkbase xmlns="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+		<calculationLink xlink:role="http://test/role/IncomeStatement">
+			<loc xlink:href="us-gaap-2024.xsd#us-gaap_GrossProfit" xlink:label="loc_GrossProfit"/>
+			<loc xlink:href="us-gaap-2024.xsd#us-gaap_Revenues" xlink:label="loc_Revenues"/>
+			<calculationArc xlink:from="loc_GrossProfit" xlink:to="loc_Revenues" order="1" weight="1"/>
+		</calculationLink>
+	</linkbase>`)
+
+	taxonomy := filingTaxonomy(edgar.Document{CalculationLinkbase: calculations})
+	require.Len(t, taxonomy.Calculations, 1)
+	assert.Equal(t, "us-gaap:GrossProfit", taxonomy.Calculations[0].From)
+	assert.NotNil(t, taxonomy.Labels("us-gaap:NetIncomeLoss"), "the standard labels should still be loaded")
+
+	taxonomy = filingTaxonomy(edgar.Document{CalculationLinkbase: []byte(`<linkbase><calculationLink>`)})
+	assert.Empty(t, taxonomy.Calculations, "a malformed calculation linkbase should be ignored")
+	assert.Empty(t, filingTaxonomy(edgar.Document{}).Calculations)
+}
+
+func TestFromEdgarExecutiveCompensation(t *testing.T) {
+	doc := []byte(`<html><body>
+		<p><b>Summary Compensation Table</b></p>
+		<table>
+			<tr>
+				<td>Name and Principal Position</td><td></td><td>Year</td><td></td>
+				<td colspan="2">Salary ($)</td><td></td><td colspan="2">Bonus ($)</td><td></td>
+				<td colspan="2">Stock Awards ($)(1)</td><td></td>
+				<td colspan="2">Non-Equity Incentive Plan Compensation ($)</td><td></td>
+				<td colspan="2">All Other Compensation ($)</td><td></td><td colspan="2">Total ($)</td>
+			</tr>
+			<tr>
+				<td><p>Jane Doe</p><p>Chief Executive Officer</p></td><td></td><td>2024</td><td></td>
+				<td>$</td><td>1,000,000</td><td></td><td></td><td>—</td><td></td>
+				<td>$</td><td>10,000,000</td><td></td>
+				<td>$</td><td>2,000,000</td><td></td>
+				<td>$</td><td>50,000</td><td></td><td>$</td><td>13,050,000</td>
+			</tr>
+			<tr>
+				<td></td><td></td><td>2023</td><td></td>
+				<td></td><td>950,000</td><td></td><td></td><td>100,000</td><td></td>
+				<td></td><td>9,000,000</td><td></td>
+				<td></td><td>1,500,000</td><td></td>
+				<td></td><td>40,000</td><td></td><td></td><td>11,590,000</td>
+			</tr>
+			<tr>
+				<td>John Roe, Chief Financial Officer</td><td></td><td>2024</td><td></td>
+				<td></td><td>600,000</td><td></td><td></td><td>—</td><td></td>
+				<td></td><td>3,000,000</td><td></td>
+				<td></td><td>500,000</td><td></td>
+				<td></td><td>20,000</td><td></td><td></td><td>4,120,000</td>
+			</tr>
+		</table>
+		<p><b>Director Compensation</b></p>
+		<table>
+			<tr><td>Name</td><td>Fees Earned or Paid in Cash ($)</td><td>Stock Awards ($)</td><td>Total ($)</td></tr>
+			<tr><td>Sam Director</td><td>$100,000</td><td>$250,000</td><td>$350,000</td></tr>
+		</table>
+		<p>Directors receive no salary.</p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "DEF 14A", FilingDate: "2025-03-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, facts.ExecutiveCompensation, 3)
+	ceo := facts.ExecutiveCompensation[0]
+	assert.Equal(t, "Jane Doe", ceo.Name)
+	assert.Equal(t, "Chief Executive Officer", ceo.Title)
+	assert.Equal(t, 2024, ceo.Year)
+	assert.Equal(t, 1000000.0, ceo.Salary)
+	assert.Equal(t, 0.0, ceo.Bonus)
+	assert.Equal(t, 10000000.0, ceo.StockAwards)
+	assert.Equal(t, 2000000.0, ceo.NonEquityIncentive)
+	assert.Equal(t, 50000.0, ceo.OtherCompensation)
+	assert.Equal(t, 13050000.0, ceo.Total)
+	assert.Equal(t, "DEF 14A", ceo.Filing.Form)
+
+	prior := facts.ExecutiveCompensation[1]
+	assert.Equal(t, "Jane Doe", prior.Name, "the name should carry over to the executive's other years")
+	assert.Equal(t, 2023, prior.Year)
+	assert.Equal(t, 100000.0, prior.Bonus)
+
+	cfo := facts.ExecutiveCompensation[2]
+	assert.Equal(t, "John Roe", cfo.Name)
+	assert.Equal(t, "Chief Financial Officer", cfo.Title)
+	assert.Equal(t, 4120000.0, cfo.Total)
+
+	require.Len(t, facts.ExecCompensationHTML, 1, "only the Summary Compensation Table should be kept")
+	assert.Contains(t, facts.ExecCompensationHTML[0], "Jane Doe")
+}
+
+func TestFromEdgarPayVersusPerformance(t *testing.T) {
+	tagged := []byte(`<html><body>
+		<xbrli:context id="fy2024">
+			<xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period>
+		</xbrli:context>
+		<xbrli:context id="fy2023">
+			<xbrli:period><xbrli:startDate>2023-01-01</xbrli:startDate><xbrli:endDate>2023-12-31</xbrli:endDate></xbrli:period>
+		</xbrli:context>
+		<xbrli:context id="fy2023-doe">
+			<xbrli:entity><xbrli:segment>
+				<xbrldi:explicitMember dimension="ecd:IndividualAxis">test:JaneDoeMember</xbrldi:explicitMember>
+			</xbrli:segment></xbrli:entity>
+			<xbrli:period><xbrli:startDate>2023-01-01</xbrli:startDate><xbrli:endDate>2023-12-31</xbrli:endDate></xbrli:period>
+		</xbrli:context>
+		<p><b>Pay Versus Performance</b></p>
+		<table>
+			<tr><td><ix:nonNumeric name="ecd:PeoName" contextRef="fy2024">Jane Doe</ix:nonNumeric></td>
+				<td><ix:nonFraction name="ecd:PeoTotalCompAmt" contextRef="fy2024" unitRef="usd">13,050,000</ix:nonFraction></td>
+				<td><ix:nonFraction name="ecd:PeoActuallyPaidCompAmt" contextRef="fy2024" unitRef="usd">20,000,000</ix:nonFraction></td>
+				<td><ix:nonFraction name="ecd:NonPeoNeoAvgTotalCompAmt" contextRef="fy2024" unitRef="usd">4,000,000</ix:nonFraction></td>
+				<td><ix:nonFraction name="ecd:TotalShareholderRtnAmt" contextRef="fy2024" unitRef="usd">150.25</ix:nonFraction></td>
+				<td><ix:nonFraction name="us-gaap:NetIncomeLoss" contextRef="fy2024" unitRef="usd" scale="6">500</ix:nonFraction></td></tr>
+			<tr><td>Jane Doe and John Roe</td>
+				<td><ix:nonFraction name="ecd:PeoTotalCompAmt" contextRef="fy2023-doe" unitRef="usd">9,000,000</ix:nonFraction></td>
+				<td><ix:nonFraction name="ecd:NonPeoNeoAvgTotalCompAmt" contextRef="fy2023" unitRef="usd">3,500,000</ix:nonFraction></td>
+				<td><ix:nonFraction name="ecd:TotalShareholderRtnAmt" contextRef="fy2023" unitRef="usd">120.00</ix:nonFraction></td></tr>
+		</table>
+	</body></html>`)
+	scraped := []byte(`<html><body>
+		<p><b>Pay Versus Performance</b></p>
+		<table>
+			<tr><td rowspan="2">Year</td><td rowspan="2">Summary Compensation Table Total for PEO ($)</td>
+				<td rowspan="2">Compensation Actually Paid to PEO ($)</td>
+				<td rowspan="2">Average Summary Compensation Table Total for Non-PEO NEOs ($)</td>
+				<td rowspan="2">Average Compensation Actually Paid to Non-PEO NEOs ($)</td>
+				<td colspan="2">Value of Initial Fixed $100 Investment Based On:</td>
+				<td rowspan="2">Net Income ($ thousands)</td></tr>
+			<tr><td>Total Shareholder Return ($)</td><td>Peer Group Total Shareholder Return ($)</td></tr>
+			<tr><td>2023</td><td>$1</td><td>$1</td><td>$1</td><td>$1</td><td>$1</td><td>$1</td><td>$1</td></tr>
+			<tr><td>2022</td><td>$8,000,000</td><td>$(2,000,000)</td><td>$3,000,000</td><td>$1,000,000</td><td>$90.50</td><td>$95.00</td><td>$400,000</td></tr>
+		</table>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "DEF 14A", FilingDate: "2025-03-01"}, DocumentFile: tagged},
+		{Filing: edgar.Filing{Form: "DEF 14A", FilingDate: "2024-03-01"}, DocumentFile: scraped},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts.PayVersusPerformance, 3)
+
+	latest := facts.PayVersusPerformance[0]
+	assert.Equal(t, 2024, latest.Year)
+	assert.Equal(t, "2024-12-31", latest.EndDate)
+	assert.Equal(t, "Jane Doe", latest.PEOName)
+	assert.Equal(t, 13050000.0, latest.PEOTotalCompensation)
+	assert.Equal(t, 20000000.0, latest.PEOCompensationActuallyPaid)
+	assert.Equal(t, 4000000.0, latest.NEOAverageTotalCompensation)
+	assert.Equal(t, 150.25, latest.TotalShareholderReturn)
+	assert.Equal(t, 500000000.0, latest.NetIncome)
+	assert.Equal(t, PayVersusPerformanceTagged, latest.Source)
+
+	prior := facts.PayVersusPerformance[1]
+	assert.Equal(t, 2023, prior.Year, "the newer proxy's values should win")
+	assert.Equal(t, PayVersusPerformanceTagged, prior.Source)
+	assert.Equal(t, 0.0, prior.PEOTotalCompensation, "a single PEO's pay in a year with two shouldn't be used for the year")
+	assert.Equal(t, 3500000.0, prior.NEOAverageTotalCompensation)
+
+	oldest := facts.PayVersusPerformance[2]
+	assert.Equal(t, 2022, oldest.Year)
+	assert.Equal(t, PayVersusPerformanceScraped, oldest.Source)
+	assert.Equal(t, "2024-03-01", oldest.Filing.FilingDate)
+	assert.Equal(t, 8000000.0, oldest.PEOTotalCompensation)
+	assert.Equal(t, -2000000.0, oldest.PEOCompensationActuallyPaid)
+	assert.Equal(t, 3000000.0, oldest.NEOAverageTotalCompensation)
+	assert.Equal(t, 1000000.0, oldest.NEOAverageCompensationActuallyPaid)
+	assert.Equal(t, 90.5, oldest.TotalShareholderReturn)
+	assert.Equal(t, 95.0, oldest.PeerGroupTotalShareholderReturn)
+	assert.Equal(t, 400000000.0, oldest.NetIncome, "the header's units should be applied")
+
+	// Headers may give their units in the singular
+	for header, scale := range map[string]float64{
+		"Net Income ($ in thousand)": 1e3,
+		"Net Income (Million)":       1e6,
+		"Net Income ($ billions)":    1e9,
+		"Net Income ($)":             1,
+	} {
+		assert.Equal(t, scale, columnScale(header), header)
+	}
+}
+
+func TestFromEdgarCEOPayRatioPrefersTagged(t *testing.T) {
+	doc := []byte(`<html><body>
+		<xbrli:context id="fy2024">
+			<xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period>
+		</xbrli:context>
+		<p><b>CEO Pay Ratio</b></p>
+		<p>The annual total compensation of our CEO was $1,000 and the median of our employees was $50,000, a ratio of 200 to 1.</p>
+		<p><b>Pay Versus Performance</b></p>
+		<p>Total compensation for our PEO was $<ix:nonFraction name="ecd:PeoTotalCompAmt" contextRef="fy2024" unitRef="usd">10,000,000</ix:nonFraction>.</p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "DEF 14A", FilingDate: "2025-03-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, facts.CEOPayRatio)
+	assert.Equal(t, 10000000.0, facts.CEOPayRatio.CEO, "the tagged CEO pay should be preferred")
+	assert.Equal(t, 50000.0, facts.CEOPayRatio.Median)
+	assert.Equal(t, 200.0, facts.CEOPayRatio.Ratio)
+}
+
+func TestExtractHumanCapital(t *testing.T) {
+	text := `Item 1. Business
+We operate 1,200 stores in 15 countries.
+Human Capital
+As of June 30, 2024, we had approximately 52,000 employees, most of whom work in our stores. Of these, 40,000 were full-time and 12,000 were part-time employees. Approximately 30,000 of our employees are located in the U.S. and 22,000 are located outside the United States.
+Approximately 18% of our U.S. employees are represented by labor unions or covered by collective bargaining agreements, and 2,500 of our employees are members of works councils in Europe.
+Our voluntary turnover rate was 14.5% in fiscal 2024.`
+
+	h := extractHumanCapital(edgar.Filing{Form: "10-K"}, text)
+	require.NotNil(t, h)
+	require.NotNil(t, h.Employees)
+	assert.Equal(t, 52000.0, h.Employees.Value)
+	assert.Equal(t, "As of June 30, 2024, we had approximately 52,000 employees, most of whom work in our stores.", h.Employees.Sentence)
+	require.NotNil(t, h.FullTime)
+	assert.Equal(t, 40000.0, h.FullTime.Value)
+	require.NotNil(t, h.PartTime)
+	assert.Equal(t, 12000.0, h.PartTime.Value)
+	require.NotNil(t, h.US)
+	assert.Equal(t, 30000.0, h.US.Value)
+	require.NotNil(t, h.International)
+	assert.Equal(t, 22000.0, h.International.Value)
+	require.NotNil(t, h.UnionizedPercent)
+	assert.Equal(t, 18.0, h.UnionizedPercent.Value)
+	require.NotNil(t, h.TurnoverPercent)
+	assert.Equal(t, 14.5, h.TurnoverPercent.Value)
+	assert.Equal(t, 52000, h.Headcount())
+
+	h = extractHumanCapital(edgar.Filing{}, `We had 2.1 million employees worldwide. None of our U.S. employees are represented by a union.`)
+	require.NotNil(t, h)
+	assert.Equal(t, 2100000.0, h.Employees.Value)
+	require.NotNil(t, h.UnionizedPercent)
+	assert.Equal(t, 0.0, h.UnionizedPercent.Value)
+
+	assert.Nil(t, extractHumanCapital(edgar.Filing{}, "We have offices in 12 countries."))
+	assert.Nil(t, extractHumanCapital(edgar.Filing{}, "Our platform reaches more than 3 billion people. We serve 50 million individuals."))
+}
+
+func TestHumanCapitalSubsections(t *testing.T) {
+	text := `Item 1. Business
+Our associates serve 50 million customers.
+Human Capital Resources
+As of December 31, 2024, we had 1,000 employees.
+Employees. We employ 900 team members.`
+
+	subsections := humanCapitalSubsections(text)
+	require.Len(t, subsections, 2)
+	assert.Equal(t, "\nAs of December 31, 2024, we had 1,000 employees.\nEmployees. We employ 900 team members.", subsections[0])
+	assert.Equal(t, "We employ 900 team members.", subsections[1])
+	assert.Empty(t, humanCapitalSubsections("Item 1. Business\nWe had 1,000 employees."))
+}
+
+func TestFromEdgarHumanCapital(t *testing.T) {
+	doc := []byte(`<html><body>
+		<p>Our customers employ over 100,000 workers.</p>
+		<p><b>Item 1. Business</b></p>
+		<p><b>Human Capital</b></p>
+		<p>As of September 28, 2024, we had approximately 8,000 full-time employees and 500 part-time employees.</p>
+		<p><b>Item 1A. Risk Factors</b></p>
+		<p>We may lose key employees.</p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2024-11-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, facts.HumanCapital)
+	assert.Nil(t, facts.HumanCapital.Employees)
+	require.NotNil(t, facts.HumanCapital.FullTime)
+	assert.Equal(t, 8000.0, facts.HumanCapital.FullTime.Value)
+	require.NotNil(t, facts.HumanCapital.PartTime)
+	assert.Equal(t, 500.0, facts.HumanCapital.PartTime.Value)
+	assert.Equal(t, 8500, facts.EmployeesCount, "the headcount should come from a fiscal year not ending in December")
+}
+
+func TestFromEdgarHumanCapitalOtherPeople(t *testing.T) {
+	doc := []byte(`<html><body>
+		<p><b>Item 1. Business</b></p>
+		<p>Our platform reaches more than 3 billion people. We serve 50 million individuals.</p>
+		<p>As of December 31, 2024, 2,000 employees of our customers use our software.</p>
+		<p><b>Human Capital</b></p>
+		<p>Our mission is to connect 3 billion people. We believe our people are our greatest strength.</p>
+		<p>Of our employees, 300 work in research and development.</p>
+		<p>As of December 31, 2024, we had approximately 1,200 employees.</p>
+		<p><b>Item 1A. Risk Factors</b></p>
+	</body></html>`)
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2025-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, facts.HumanCapital)
+	require.NotNil(t, facts.HumanCapital.Employees)
+	assert.Equal(t, 1200.0, facts.HumanCapital.Employees.Value)
+	assert.Equal(t, 1200, facts.EmployeesCount)
+
+	// A number of employees that isn't stated as the headcount doesn't
+	// replace the one found before
+	doc = []byte(`<html><body>
+		<p><b>Item 1. Business</b></p>
+		<p>As of December 31, 2024, we had 1,800 employees.</p>
+		<p><b>Human Capital</b></p>
+		<p>In 2024, 2,000 employees completed our training programs.</p>
+		<p><b>Item 1A. Risk Factors</b></p>
+	</body></html>`)
+	facts, err = FromEdgar("123", "TEST", "Test Corp", []edgar.Document{
+		{Filing: edgar.Filing{Form: "10-K", FilingDate: "2025-02-01"}, DocumentFile: doc},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, facts.HumanCapital)
+	assert.Equal(t, 2000.0, facts.HumanCapital.Employees.Value)
+	assert.Equal(t, 1800, facts.EmployeesCount)
+	assert.Equal(t, "text:employees", facts.EmployeesCountSource.Extractor)
+}
+
+func TestFromEdgarProvenance(t *testing.T) {
+	doc := []byte(`<html><body>
+		<xbrli:context id="c-1"><xbrli:period>
+			<xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate>
+		</xbrli:period></xbrli:context>
+		<p><b>Item 1. Business</b></p>
+		<p><b>Employees</b></p>
+		<p id="hc">As of December 31, 2024, we had approximately 1,500 employees.</p>
+		<p><b>Item 7. Management's Discussion and Analysis</b></p>
+		<p>Net income was $<ix:nonfraction id="f-1" unitref="usd" contextref="c-1" name="us-gaap:NetIncomeLoss" scale="6">100</ix:nonfraction> million.</p>
+	</body></html>`)
+	filing := edgar.Filing{CIK: "123", AccessionNumber: "0000000123-25-000001", Form: "10-K", FilingDate: "2025-02-01", PrimaryDocument: "test-10k.htm"}
+
+	facts, err := FromEdgar("123", "TEST", "Test Corp", []edgar.Document{{Filing: filing, DocumentFile: doc}})
+	require.NoError(t, err)
+
+	require.Len(t, facts.NetIncomeLoss, 1)
+	src := facts.NetIncomeLoss[0].Source
+	require.NotNil(t, src)
+	assert.Equal(t, "0000000123-25-000001", src.Accession)
+	assert.Equal(t, "f-1", src.FactID)
+	assert.Equal(t, "ixbrl:us-gaap:NetIncomeLoss", src.Extractor)
+	assert.Equal(t, "https://www.sec.gov/Archives/edgar/data/123/000000012325000001/test-10k.htm#f-1", src.URL())
+
+	assert.Equal(t, 1500, facts.EmployeesCount)
+	src = facts.EmployeesCountSource
+	require.NotNil(t, src)
+	assert.Equal(t, "text:human-capital", src.Extractor)
+	assert.Equal(t, "hc", src.ElementID)
+	assert.Equal(t, "As of December 31, 2024, we had approximately 1,500 employees.", src.Snippet)
+	assert.Contains(t, src.URL(), "test-10k.htm#:~:text=As%20of%20December%2031")
+	require.NotNil(t, facts.HumanCapital)
+	assert.Equal(t, src, facts.HumanCapital.Employees.Source)
+
+	facts.AddCompanyFacts(&edgar.CompanyFacts{Facts: map[string]map[string]edgar.Concept{
+		"us-gaap": {"NetIncomeLoss": {Units: map[string][]edgar.ConceptValue{
+			"USD": {
+				{Start: "2024-01-01", End: "2024-12-31", Val: 100e6, Accn: "0000000123-25-000001", Form: "10-K", FP: "FY"},
+				{Start: "2023-01-01", End: "2023-12-31", Val: 90e6, Accn: "0000000123-25-000001", Form: "10-K", FP: "FY"},
+				{Start: "2022-01-01", End: "2022-12-31", Val: 80e6, Accn: "0000000123-23-000001", Form: "10-K", FP: "FY"},
+			},
+		}}},
+	}})
+	require.Len(t, facts.NetIncomeLoss, 3)
+	src = facts.NetIncomeLoss[0].Source
+	require.NotNil(t, src)
+	assert.Equal(t, "ixbrl:us-gaap:NetIncomeLoss", src.Extractor, "the fact parsed from the filing should still be linked to")
+	assert.Equal(t, "https://www.sec.gov/Archives/edgar/data/123/000000012325000001/test-10k.htm#f-1", src.URL())
+
+	src = facts.NetIncomeLoss[1].Source
+	require.NotNil(t, src)
+	assert.Equal(t, "companyfacts:us-gaap:NetIncomeLoss", src.Extractor)
+	assert.Equal(t, "https://www.sec.gov/Archives/edgar/data/123/000000012325000001/test-10k.htm", src.URL(), "a known filing's document should be linked to")
+
+	src = facts.NetIncomeLoss[2].Source
+	require.NotNil(t, src)
+	assert.Equal(t, "https://www.sec.gov/Archives/edgar/data/123/000000012323000001/", src.URL(), "an unknown filing's directory should be linked to")
+}
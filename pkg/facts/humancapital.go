@@ -0,0 +1,313 @@
+package facts
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/saranrapjs/labor-leverage/pkg/edgar"
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+)
+
+// HumanCapitalFigure is a number read from a human capital disclosure,
+// with the sentence it was read from so that it can be checked
+type HumanCapitalFigure struct {
+	Value    float64           `json:"value"`
+	Sentence string            `json:"sentence"`
+	Source   *ixbrl.Provenance `json:"source,omitempty"`
+}
+
+// HumanCapital holds the workforce figures a company discloses in the
+// "Human Capital" part of Item 1 of its 10-K. Companies choose what to
+// disclose, so any of them may be missing. Percentages are out of 100.
+type HumanCapital struct {
+	Filing    edgar.Filing        `json:"filing"`
+	Employees *HumanCapitalFigure `json:"employees,omitempty"`
+	FullTime  *HumanCapitalFigure `json:"full_time,omitempty"`
+	PartTime  *HumanCapitalFigure `json:"part_time,omitempty"`
+	// US and International are headcounts inside and outside the US
+	US            *HumanCapitalFigure `json:"us,omitempty"`
+	International *HumanCapitalFigure `json:"international,omitempty"`
+	// UnionizedPercent is the share of employees represented by unions,
+	// works councils or covered by collective bargaining agreements
+	UnionizedPercent *HumanCapitalFigure `json:"unionized_percent,omitempty"`
+	// TurnoverPercent is the annual (usually voluntary) turnover rate
+	TurnoverPercent *HumanCapitalFigure `json:"turnover_percent,omitempty"`
+}
+
+// Headcount returns the total number of employees, adding up full-time
+// and part-time employees where no total is given, or 0 if unknown
+func (h *HumanCapital) Headcount() int {
+	switch {
+	case h == nil:
+		return 0
+	case h.Employees != nil:
+		return int(h.Employees.Value)
+	case h.FullTime != nil && h.PartTime != nil:
+		return int(h.FullTime.Value + h.PartTime.Value)
+	case h.FullTime != nil:
+		return int(h.FullTime.Value)
+	}
+	return 0
+}
+
+// headcountFigure returns the figure Headcount is mostly based on
+func (h *HumanCapital) headcountFigure() *HumanCapitalFigure {
+	switch {
+	case h == nil:
+		return nil
+	case h.Employees != nil:
+		return h.Employees
+	}
+	return h.FullTime
+}
+
+// statedHeadcount returns Headcount and the figure it's mostly based on,
+// if that figure is stated as the company's headcount, as in "we had
+// approximately 52,000 employees", rather than being any number of
+// employees. It returns 0 and nil otherwise.
+func (h *HumanCapital) statedHeadcount() (int, *HumanCapitalFigure) {
+	figure := h.headcountFigure()
+	if figure == nil || !headcountCueRegex.MatchString(figure.Sentence) {
+		return 0, nil
+	}
+	return h.Headcount(), figure
+}
+
+// figures returns the figures that were disclosed
+func (h *HumanCapital) figures() []*HumanCapitalFigure {
+	if h == nil {
+		return nil
+	}
+	var figures []*HumanCapitalFigure
+	for _, f := range []*HumanCapitalFigure{h.Employees, h.FullTime, h.PartTime, h.US, h.International, h.UnionizedPercent, h.TurnoverPercent} {
+		if f != nil {
+			figures = append(figures, f)
+		}
+	}
+	return figures
+}
+
+var (
+	// humanCapitalNumberRegex matches counts and percentages like
+	// "164,000", "2.1 million" and "12%"
+	humanCapitalNumberRegex = regexp.MustCompile(`(?i)(\d{1,3}(?:,\d{3})+|\d+(?:\.\d+)?)(?:\s*(thousand|million)\b)?(\s*(?:%|percent\b))?`)
+	// workforceWordsRegex picks the sentences that are about the workforce
+	workforceWordsRegex = regexp.MustCompile(`(?i)\b(?:employees?|team\s+members|associates|workers|workforce|colleagues|staff|personnel|ftes)\b`)
+	// employeeWordsRegex matches what a count must be of to be a
+	// headcount: "people" and "individuals" are as often customers
+	employeeWordsRegex = regexp.MustCompile(`(?i)\b(?:employees?|team\s+members|ftes|full[\s-]time[\s-]equivalents)\b`)
+	// headcountCueRegex matches the words stating a company's headcount
+	headcountCueRegex       = regexp.MustCompile(`(?i)\b(?:had|have|has|employ|employs|employed|headcount)\b|\bworkforce\s+(?:of|was|consisted|comprised|included|totaled)\b|\btotal\s+of\b`)
+	fullTimeEquivalentRegex = regexp.MustCompile(`(?i)full[\s-]time[\s-]equivalent`)
+	fullTimeRegex           = regexp.MustCompile(`(?i)full[\s-]time`)
+	partTimeRegex           = regexp.MustCompile(`(?i)part[\s-]time`)
+	internationalRegex      = regexp.MustCompile(`(?i)outside\s+(?:of\s+)?(?:the\s+)?(?:united\s+states|u\.s\.|us\b)|international|non-u\.s\.|other\s+countries|abroad|foreign`)
+	usRegex                 = regexp.MustCompile(`(?i)united\s+states|u\.s\.|domestic`)
+	unionRegex              = regexp.MustCompile(`(?i)\bunion|collective(?:ly)?\s+bargain|works\s+council|labor\s+agreement`)
+	noUnionRegex            = regexp.MustCompile(`(?i)\b(?:none|no)\s+of\s+our\s+(?:[\w.-]+\s+){0,3}?(?:employees|workforce)\s+(?:is|are)\s+(?:currently\s+)?(?:represented|covered|members)`)
+	turnoverRegex           = regexp.MustCompile(`(?i)turnover|attrition`)
+	monthRegex              = regexp.MustCompile(`(?i)(?:january|february|march|april|may|june|july|august|september|october|november|december)\s*$`)
+)
+
+// humanCapitalContext is how far after a count to look for what it counts
+const humanCapitalContext = 80
+
+var (
+	// humanCapitalHeadingRegex matches the headings of the human capital
+	// disclosure, e.g. "Human Capital Resources", "Employees" or "Our
+	// People and Culture"
+	humanCapitalHeadingRegex = regexp.MustCompile(`(?i)^(?:our\s+)?(?:human\s+capital|employees|people|workforce)(?:\s+[\w&,]+){0,4}$`)
+	// humanCapitalRunInRegex matches the same headings run into the first
+	// paragraph, e.g. "Employees. As of December 31, ..."
+	humanCapitalRunInRegex = regexp.MustCompile(`(?i)^(?:our\s+)?(?:human\s+capital(?:\s+(?:resources|management))?|employees)\s*[.:–—]\s+`)
+)
+
+const (
+	// maxHeadingLength is the longest line taken for a heading
+	maxHeadingLength = 60
+	// maxHumanCapitalText caps the text read after a heading, as the end
+	// of the disclosure isn't marked
+	maxHumanCapitalText = 10000
+)
+
+// humanCapitalSubsections returns the text following each heading like
+// that of the human capital disclosure in a 10-K's Item 1, up to
+// maxHumanCapitalText. A table of contents may list the heading before
+// the disclosure itself, so there may be several.
+func humanCapitalSubsections(text string) []string {
+	lines := strings.Split(text, "\n")
+	var subsections []string
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		var b strings.Builder
+		switch {
+		case len(line) <= maxHeadingLength && humanCapitalHeadingRegex.MatchString(line):
+		case humanCapitalRunInRegex.MatchString(line):
+			b.WriteString(line[len(humanCapitalRunInRegex.FindString(line)):])
+		default:
+			continue
+		}
+		for _, next := range lines[i+1:] {
+			if b.Len() >= maxHumanCapitalText {
+				break
+			}
+			b.WriteString("\n")
+			b.WriteString(next)
+		}
+		subsections = append(subsections, b.String())
+	}
+	return subsections
+}
+
+// extractHumanCapital reads the workforce figures from the text of a
+// 10-K's human capital disclosure. Each figure is the first one found in the text, which
+// is usually as of the end of the fiscal year, whenever that is.
+func extractHumanCapital(filing edgar.Filing, text string) *HumanCapital {
+	h := &HumanCapital{Filing: filing}
+	set := func(field **HumanCapitalFigure, value float64, sentence string) {
+		if *field == nil {
+			*field = &HumanCapitalFigure{Value: value, Sentence: sentence}
+		}
+	}
+
+	for _, sentence := range sentences(text) {
+		// Turnover rates are often given without mentioning employees
+		isUnion := unionRegex.MatchString(sentence)
+		isTurnover := turnoverRegex.MatchString(sentence)
+		if !workforceWordsRegex.MatchString(sentence) && !isTurnover {
+			continue
+		}
+		if isUnion && noUnionRegex.MatchString(sentence) {
+			set(&h.UnionizedPercent, 0, sentence)
+		}
+
+		matches := humanCapitalNumberRegex.FindAllStringSubmatchIndex(sentence, -1)
+		previous := 0
+		for i, m := range matches {
+			preceding := sentence[previous:m[0]]
+			previous = m[1]
+
+			number := sentence[m[2]:m[3]]
+			value, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+			if err != nil {
+				continue
+			}
+
+			// Percentages are only of interest for unions and turnover
+			if m[6] >= 0 {
+				switch {
+				case isUnion && !isTurnover:
+					set(&h.UnionizedPercent, value, sentence)
+				case isTurnover && !isUnion:
+					set(&h.TurnoverPercent, value, sentence)
+				}
+				continue
+			}
+			// Counts in these sentences are of the employees in unions or
+			// who left, not headcounts
+			if isUnion || isTurnover {
+				continue
+			}
+
+			// What a count counts is said in the words following it, up to
+			// the next number or clause, so that "10,000 employees, most of
+			// whom are in the U.S." is a total
+			end := min(len(sentence), m[1]+humanCapitalContext)
+			if i+1 < len(matches) {
+				end = min(end, matches[i+1][0])
+			}
+			following := sentence[m[1]:end]
+			if clause := strings.IndexAny(following, ",;:("); clause >= 0 {
+				following = following[:clause]
+			}
+
+			// Skip the days and years of dates, e.g. "December 31, 2024"
+			if monthRegex.MatchString(preceding) || isYear(number, following) {
+				continue
+			}
+			if m[4] >= 0 {
+				switch strings.ToLower(sentence[m[4]:m[5]]) {
+				case "thousand":
+					value *= 1e3
+				case "million":
+					value *= 1e6
+				}
+			}
+
+			switch {
+			case fullTimeEquivalentRegex.MatchString(following) && employeeWordsRegex.MatchString(following):
+				set(&h.Employees, value, sentence)
+			case partTimeRegex.MatchString(following):
+				set(&h.PartTime, value, sentence)
+			case fullTimeRegex.MatchString(following):
+				set(&h.FullTime, value, sentence)
+			case internationalRegex.MatchString(following):
+				set(&h.International, value, sentence)
+			case usRegex.MatchString(following):
+				set(&h.US, value, sentence)
+			case !employeeWordsRegex.MatchString(following):
+				// Not a headcount, e.g. a number of countries or stores
+			case internationalRegex.MatchString(preceding):
+				set(&h.International, value, sentence)
+			case usRegex.MatchString(preceding):
+				set(&h.US, value, sentence)
+			default:
+				set(&h.Employees, value, sentence)
+			}
+		}
+	}
+
+	if len(h.figures()) == 0 {
+		return nil
+	}
+	return h
+}
+
+// isYear reports whether a number is likely a year rather than a count:
+// four digits without a thousands separator, not directly followed by
+// the people it counts
+func isYear(number, following string) bool {
+	if len(number) != 4 || (number[:2] != "19" && number[:2] != "20") {
+		return false
+	}
+	loc := employeeWordsRegex.FindStringIndex(following)
+	return loc == nil || strings.TrimSpace(following[:loc[0]]) != ""
+}
+
+// abbreviations end in a period without ending a sentence
+var abbreviations = map[string]bool{
+	"approx.": true, "inc.": true, "no.": true, "mr.": true, "ms.": true, "dr.": true, "co.": true, "corp.": true,
+}
+
+// sentences splits text into sentences, treating line breaks between
+// blocks as sentence breaks
+func sentences(text string) []string {
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		words := strings.Fields(line)
+		start := 0
+		for i, word := range words {
+			last := word[len(word)-1]
+			if last != '.' && last != '!' && last != '?' {
+				continue
+			}
+			// Abbreviations like "U.S." and "Inc." don't end sentences, and
+			// a new sentence starts with a capital letter
+			lower := strings.ToLower(word)
+			if abbreviations[lower] || strings.Count(strings.TrimSuffix(word, "."), ".") > 0 {
+				continue
+			}
+			if i+1 < len(words) && !unicode.IsUpper([]rune(words[i+1])[0]) {
+				continue
+			}
+			result = append(result, strings.Join(words[start:i+1], " "))
+			start = i + 1
+		}
+		if start < len(words) {
+			result = append(result, strings.Join(words[start:], " "))
+		}
+	}
+	return result
+}
@@ -0,0 +1,256 @@
+package facts
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/saranrapjs/labor-leverage/pkg/edgar"
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+)
+
+// Sources of PayVersusPerformance values
+const (
+	// PayVersusPerformanceTagged values come from the ecd: iXBRL tags
+	PayVersusPerformanceTagged = "ecd"
+	// PayVersusPerformanceScraped values were read from the HTML table, for
+	// proxy statements that predate the tags
+	PayVersusPerformanceScraped = "table"
+)
+
+// PayVersusPerformance is a fiscal year of a proxy statement's Pay versus
+// Performance disclosure, which compares the pay of the principal
+// executive officer (PEO, usually the CEO) and the average pay of the
+// other named executive officers (NEOs) with the company's performance.
+//
+// "Compensation actually paid" adjusts the Summary Compensation Table's
+// total for changes in the value of equity awards over the year. Total
+// shareholder return is the value of $100 invested in the company's stock
+// at the start of the period the table covers.
+type PayVersusPerformance struct {
+	Filing    edgar.Filing `json:"filing"`
+	Year      int          `json:"year"`
+	StartDate string       `json:"start_date,omitempty"`
+	EndDate   string       `json:"end_date,omitempty"`
+	// PEOName is only set where the filing tags it
+	PEOName                            string  `json:"peo_name,omitempty"`
+	PEOTotalCompensation               float64 `json:"peo_total_compensation,omitempty"`
+	PEOCompensationActuallyPaid        float64 `json:"peo_compensation_actually_paid,omitempty"`
+	NEOAverageTotalCompensation        float64 `json:"neo_average_total_compensation,omitempty"`
+	NEOAverageCompensationActuallyPaid float64 `json:"neo_average_compensation_actually_paid,omitempty"`
+	TotalShareholderReturn             float64 `json:"total_shareholder_return,omitempty"`
+	PeerGroupTotalShareholderReturn    float64 `json:"peer_group_total_shareholder_return,omitempty"`
+	NetIncome                          float64 `json:"net_income,omitempty"`
+	// Source is PayVersusPerformanceTagged or PayVersusPerformanceScraped
+	Source string `json:"source"`
+	// Provenance is where the year's values were read from: the first of
+	// its tagged facts, or its table row
+	Provenance *ixbrl.Provenance `json:"provenance,omitempty"`
+}
+
+// payVersusPerformanceConcepts maps the ecd: concepts to the values they tag
+var payVersusPerformanceConcepts = map[string]func(*PayVersusPerformance) *float64{
+	"ecd:PeoTotalCompAmt":                 func(p *PayVersusPerformance) *float64 { return &p.PEOTotalCompensation },
+	"ecd:PeoActuallyPaidCompAmt":          func(p *PayVersusPerformance) *float64 { return &p.PEOCompensationActuallyPaid },
+	"ecd:NonPeoNeoAvgTotalCompAmt":        func(p *PayVersusPerformance) *float64 { return &p.NEOAverageTotalCompensation },
+	"ecd:NonPeoNeoAvgCompActuallyPaidAmt": func(p *PayVersusPerformance) *float64 { return &p.NEOAverageCompensationActuallyPaid },
+	"ecd:TotalShareholderRtnAmt":          func(p *PayVersusPerformance) *float64 { return &p.TotalShareholderReturn },
+	"ecd:PeerGroupTotalShareholderRtnAmt": func(p *PayVersusPerformance) *float64 { return &p.PeerGroupTotalShareholderReturn },
+}
+
+// taggedPayVersusPerformance reads the years of a document's ecd: Pay
+// versus Performance facts. Only values for the company as a whole are
+// used: in years with more than one PEO, each PEO's pay is tagged with
+// ecd:IndividualAxis, and the year's PEO values are left empty.
+func taggedPayVersusPerformance(filing edgar.Filing, doc *ixbrl.Document) []PayVersusPerformance {
+	type period struct{ start, end string }
+	years := map[period]*PayVersusPerformance{}
+	var order []period
+
+	yearOf := func(context *ixbrl.Context) (period, bool) {
+		if context == nil || context.IsDimensioned() {
+			return period{}, false
+		}
+		p := period{strings.TrimSpace(context.Period.StartDate), strings.TrimSpace(context.Period.EndDate)}
+		return p, p.end != ""
+	}
+
+	for _, nf := range ixbrl.FilterByType(doc.Nodes, func(*ixbrl.NonFraction) bool { return true }) {
+		value, ok := payVersusPerformanceConcepts[nf.Name]
+		if !ok {
+			continue
+		}
+		p, ok := yearOf(nf.Context)
+		if !ok {
+			continue
+		}
+		amount, err := nf.ScaledValue()
+		if err != nil {
+			continue
+		}
+		year, ok := years[p]
+		if !ok {
+			year = &PayVersusPerformance{
+				Filing:    filing,
+				Year:      fiscalYear(p.end),
+				StartDate: p.start,
+				EndDate:   p.end,
+				Source:    PayVersusPerformanceTagged,
+			}
+			year.Provenance = source(filing, "", doc.FactProvenance(nf))
+			years[p] = year
+			order = append(order, p)
+		}
+		*value(year) = amount
+	}
+	if len(years) == 0 {
+		return nil
+	}
+
+	// Net income is tagged with us-gaap's concept, for the same years
+	for _, p := range order {
+		if nf := doc.Undimensioned("us-gaap:NetIncomeLoss", &ixbrl.Period{StartDate: p.start, EndDate: p.end}); nf != nil {
+			years[p].NetIncome, _ = nf.ScaledValue()
+		}
+	}
+	for _, nn := range ixbrl.FilterByType(doc.Nodes, func(nn *ixbrl.NonNumeric) bool { return nn.Name == "ecd:PeoName" }) {
+		if p, ok := yearOf(nn.Context); ok && years[p] != nil {
+			years[p].PEOName = strings.TrimSpace(nn.Content)
+		}
+	}
+
+	var series []PayVersusPerformance
+	for _, p := range order {
+		series = append(series, *years[p])
+	}
+	return series
+}
+
+// payVersusPerformanceColumns identifies the Pay versus Performance
+// table's columns by their headers, in order of precedence: e.g. the
+// NEO columns also mention compensation actually paid.
+var payVersusPerformanceColumns = []struct {
+	regex *regexp.Regexp
+	value func(*PayVersusPerformance) *float64
+}{
+	{regexp.MustCompile(`(?i)average.*actually\s+paid`), func(p *PayVersusPerformance) *float64 { return &p.NEOAverageCompensationActuallyPaid }},
+	{regexp.MustCompile(`(?i)average.*(summary\s+compensation|sct|total)`), func(p *PayVersusPerformance) *float64 { return &p.NEOAverageTotalCompensation }},
+	{regexp.MustCompile(`(?i)actually\s+paid`), func(p *PayVersusPerformance) *float64 { return &p.PEOCompensationActuallyPaid }},
+	{regexp.MustCompile(`(?i)(summary\s+compensation\s+table|sct)\s+total`), func(p *PayVersusPerformance) *float64 { return &p.PEOTotalCompensation }},
+	{regexp.MustCompile(`(?i)peer\s+group`), func(p *PayVersusPerformance) *float64 { return &p.PeerGroupTotalShareholderReturn }},
+	{regexp.MustCompile(`(?i)total\s+shareholder\s+return|\btsr\b`), func(p *PayVersusPerformance) *float64 { return &p.TotalShareholderReturn }},
+	{regexp.MustCompile(`(?i)net\s+income`), func(p *PayVersusPerformance) *float64 { return &p.NetIncome }},
+}
+
+// columnScaleRegex matches the units a column's header gives its
+// amounts in, e.g. "Net Income ($ thousands)", "(in millions)" or
+// "($ in thousand)"
+var columnScaleRegex = regexp.MustCompile(`(?i)\b(thousand|million|billion)s?\b`)
+
+// columnScale returns what a column's amounts are multiplied by to be in
+// dollars, given its header
+func columnScale(header string) float64 {
+	m := columnScaleRegex.FindStringSubmatch(header)
+	if m == nil {
+		return 1
+	}
+	scale, _ := parseDollarAmount("1", m[1])
+	return scale
+}
+
+// scrapedPayVersusPerformance reads the years of a Pay versus Performance
+// table from its HTML. Where a table has columns for several PEOs, the
+// first is used.
+func scrapedPayVersusPerformance(filing edgar.Filing, table ixbrl.Table) []PayVersusPerformance {
+	yearColumn := -1
+	columns := map[int]func(*PayVersusPerformance) *float64{}
+	scales := map[int]float64{}
+	matched := map[int]bool{}
+	for col, header := range table.Headers() {
+		if header == "" {
+			continue
+		}
+		if yearColumn < 0 && yearColumnRegex.MatchString(header) && !strings.Contains(strings.ToLower(header), "compensation") {
+			yearColumn = col
+			continue
+		}
+		for i, c := range payVersusPerformanceColumns {
+			if c.regex.MatchString(header) {
+				if !matched[i] {
+					columns[col] = c.value
+					scales[col] = columnScale(header)
+					matched[i] = true
+				}
+				break
+			}
+		}
+	}
+	if yearColumn < 0 || len(columns) == 0 {
+		return nil
+	}
+
+	var series []PayVersusPerformance
+	for _, row := range table.Body() {
+		year := row[yearColumn]
+		if !year.Numeric || year.Value < 1900 {
+			continue
+		}
+		p := PayVersusPerformance{Filing: filing, Year: int(year.Value), Source: PayVersusPerformanceScraped}
+		if year.Node != nil {
+			p.Provenance = source(filing, "table:pay-versus-performance", ixbrl.NodeProvenance(year.Node.Parent, ""))
+		}
+		for col, value := range columns {
+			if row[col].Numeric {
+				*value(&p) = row[col].Value * scales[col]
+			}
+		}
+		series = append(series, p)
+	}
+	return series
+}
+
+// addPayVersusPerformance adds the years of a filing's Pay versus
+// Performance disclosure that aren't already known from a more recent
+// filing, preferring its ecd: tags to scraping its table. It returns all
+// of the filing's years, newest first.
+func (f *Facts) addPayVersusPerformance(filing edgar.Filing, doc *ixbrl.Document, sections []ixbrl.Section) []PayVersusPerformance {
+	series := taggedPayVersusPerformance(filing, doc)
+	if len(series) == 0 {
+		section, ok := ixbrl.FindSection(sections, ixbrl.SectionPayVersusPerformance)
+		if !ok {
+			return nil
+		}
+		tables := section.FindTables(func(text string) bool {
+			return strings.Contains(strings.ToLower(text), "actually paid")
+		})
+		for _, t := range tables {
+			if series = scrapedPayVersusPerformance(filing, ixbrl.NormalizeTable(t)); len(series) > 0 {
+				break
+			}
+		}
+	}
+
+	slices.SortStableFunc(series, func(a, b PayVersusPerformance) int {
+		return b.Year - a.Year
+	})
+	for _, year := range series {
+		if !slices.ContainsFunc(f.PayVersusPerformance, func(p PayVersusPerformance) bool { return p.Year == year.Year }) {
+			f.PayVersusPerformance = append(f.PayVersusPerformance, year)
+		}
+	}
+	// Newest first, like the other series
+	slices.SortStableFunc(f.PayVersusPerformance, func(a, b PayVersusPerformance) int {
+		return b.Year - a.Year
+	})
+	return series
+}
+
+// fiscalYear returns the year of a fiscal year's end date
+func fiscalYear(endDate string) int {
+	t, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return 0
+	}
+	return t.Year()
+}
@@ -0,0 +1,224 @@
+package facts
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+)
+
+// PayRatioConfidence describes how well the figures of a CEO pay ratio
+// disclosure could be identified and checked against each other
+type PayRatioConfidence string
+
+const (
+	// PayRatioHigh means the CEO's and median employee's pay were both
+	// identified and agree with the stated ratio
+	PayRatioHigh PayRatioConfidence = "high"
+	// PayRatioMedium means one of the three figures was derived from the
+	// other two, or the amounts were told apart only by the stated ratio
+	PayRatioMedium PayRatioConfidence = "medium"
+	// PayRatioLow means the figures are a guess from the largest and
+	// smallest dollar amounts, or contradict the stated ratio
+	PayRatioLow PayRatioConfidence = "low"
+)
+
+// rank orders confidence levels, for picking the best of several readings
+func (c PayRatioConfidence) rank() int {
+	switch c {
+	case PayRatioHigh:
+		return 3
+	case PayRatioMedium:
+		return 2
+	case PayRatioLow:
+		return 1
+	}
+	return 0
+}
+
+// CEOPayRatio is a proxy statement's CEO pay ratio disclosure: the CEO's
+// and the median employee's annual total compensation, and how many times
+// the median the CEO was paid, with the text they were read from.
+type CEOPayRatio struct {
+	Text   string
+	CEO    float64
+	Median float64
+	// Ratio is the stated ratio of the CEO's pay to the median employee's,
+	// i.e. N in "N to 1", or the ratio of the amounts if none is stated
+	Ratio      float64
+	Confidence PayRatioConfidence
+	Source     *ixbrl.Provenance
+
+	// medianStated is whether the median employee's pay was identified
+	// in the text, rather than derived from the ratio or guessed
+	medianStated bool
+}
+
+var (
+	// payRatioRegex matches stated ratios like "650 to 1" and "650:1"
+	payRatioRegex = regexp.MustCompile(`(?i)((?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?)\s*(?:to|:)\s*1\b`)
+	// ceoRegex and medianRegex identify whose pay an amount is
+	ceoRegex    = regexp.MustCompile(`(?i)\b(?:ceo|chief\s+executive|principal\s+executive)`)
+	medianRegex = regexp.MustCompile(`(?i)\bmedian\b`)
+)
+
+// payRatioContext is how far before an amount to look for whose pay it is
+const payRatioContext = 200
+
+// dollarAmount is an amount in a pay ratio disclosure, with the role
+// ("ceo" or "median") its preceding text attributes it to
+type dollarAmount struct {
+	value float64
+	role  string
+}
+
+// extractCEOPayRatio reads a CEO pay ratio disclosure: the stated ratio,
+// and the CEO's and median employee's annual total compensation, which
+// are told apart by the words preceding them. It reports false if the
+// text has neither a ratio nor two dollar amounts.
+func extractCEOPayRatio(text string) (CEOPayRatio, bool) {
+	result := CEOPayRatio{Text: text}
+
+	// The stated ratio, skipping e.g. "1 to 1,000 employees"
+	for _, m := range payRatioRegex.FindAllStringSubmatchIndex(text, -1) {
+		if rest := text[m[1]:]; len(rest) > 1 && (rest[0] == ',' || rest[0] == '.') && isDigit(rest[1]) {
+			continue
+		}
+		ratio, err := strconv.ParseFloat(strings.ReplaceAll(text[m[2]:m[3]], ",", ""), 64)
+		if err == nil && ratio > 1 {
+			result.Ratio = ratio
+			break
+		}
+	}
+
+	var amounts []dollarAmount
+	previous := 0
+	for _, m := range dollarAmountRegex.FindAllStringSubmatchIndex(text, -1) {
+		var scale string
+		if m[4] >= 0 {
+			scale = text[m[4]:m[5]]
+		}
+		value, err := parseDollarAmount(text[m[2]:m[3]], scale)
+		if err != nil || value == 0 {
+			continue
+		}
+		// Whose pay it is is said between the previous amount and this one,
+		// and the first mention wins, as in "the median of the
+		// compensation of all employees other than our CEO was $X"
+		start := max(previous, m[0]-payRatioContext)
+		preceding := text[start:m[0]]
+		amount := dollarAmount{value: value}
+		ceo, median := ceoRegex.FindStringIndex(preceding), medianRegex.FindStringIndex(preceding)
+		switch {
+		case median != nil && (ceo == nil || median[0] < ceo[0]):
+			amount.role = "median"
+		case ceo != nil:
+			amount.role = "ceo"
+		}
+		amounts = append(amounts, amount)
+		previous = m[1]
+	}
+
+	var ceo, median float64
+	for _, a := range amounts {
+		if a.role == "ceo" && ceo == 0 {
+			ceo = a.value
+		}
+		if a.role == "median" && median == 0 {
+			median = a.value
+		}
+	}
+
+	switch {
+	case ceo > 0 && median > 0 && ceo > median:
+		result.CEO, result.Median = ceo, median
+		result.medianStated = true
+		switch {
+		case result.Ratio == 0:
+			result.Ratio = math.Round(ceo / median)
+			result.Confidence = PayRatioMedium
+		case ratioAgrees(ceo, median, result.Ratio):
+			result.Confidence = PayRatioHigh
+		default:
+			result.Confidence = PayRatioLow
+		}
+	case result.Ratio > 0 && ceo > 0:
+		result.CEO, result.Median = ceo, ceo/result.Ratio
+		result.Confidence = PayRatioMedium
+	case result.Ratio > 0 && median > 0:
+		result.CEO, result.Median = median*result.Ratio, median
+		result.Confidence = PayRatioMedium
+		result.medianStated = true
+	default:
+		// Without words to go by, a pair of amounts agreeing with the ratio
+		// is still a good reading
+		if result.Ratio > 0 {
+			for _, c := range amounts {
+				for _, m := range amounts {
+					if c.value > m.value && ratioAgrees(c.value, m.value, result.Ratio) && result.CEO == 0 {
+						result.CEO, result.Median = c.value, m.value
+						result.Confidence = PayRatioMedium
+					}
+				}
+			}
+			if result.CEO > 0 {
+				break
+			}
+		}
+		// Otherwise fall back to the largest and smallest amounts
+		if len(amounts) < 2 {
+			return result, result.Ratio > 0
+		}
+		result.CEO, result.Median = amounts[0].value, amounts[0].value
+		for _, a := range amounts {
+			result.CEO = max(result.CEO, a.value)
+			result.Median = min(result.Median, a.value)
+		}
+		if result.CEO == result.Median {
+			return result, result.Ratio > 0
+		}
+		if result.Ratio == 0 {
+			result.Ratio = math.Round(result.CEO / result.Median)
+		}
+		result.Confidence = PayRatioLow
+	}
+	return result, true
+}
+
+// withTaggedCEOPay prefers the CEO's total compensation as tagged with
+// ecd:PeoTotalCompAmt in the Pay versus Performance table, which is the
+// Summary Compensation Table total the pay ratio is usually based on. The
+// ecd taxonomy has no tags for the pay ratio itself, so the median
+// employee's pay still comes from the text, and is only derived from the
+// tagged amount where the text doesn't state it.
+func (r CEOPayRatio) withTaggedCEOPay(ceo float64) CEOPayRatio {
+	switch {
+	case ceo <= 0:
+		return r
+	case r.Median > 0 && ratioAgrees(ceo, r.Median, r.Ratio):
+		r.CEO = ceo
+	case r.medianStated:
+		// The stated median wins over one derived from the tagged amount:
+		// the ratio may be based on an annualized figure for a CEO who
+		// joined mid-year
+	case r.Ratio > 0:
+		r.CEO, r.Median = ceo, ceo/r.Ratio
+		r.Confidence = PayRatioMedium
+	}
+	return r
+}
+
+// ratioAgrees reports whether two amounts have the stated ratio, allowing
+// for it being rounded to a whole number
+func ratioAgrees(ceo, median, ratio float64) bool {
+	if median <= 0 {
+		return false
+	}
+	return math.Abs(ceo/median-ratio) <= max(1, ratio*0.01)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,55 @@
+package facts
+
+import (
+	"strings"
+
+	"github.com/saranrapjs/labor-leverage/pkg/edgar"
+	"github.com/saranrapjs/labor-leverage/pkg/ixbrl"
+	"golang.org/x/net/html"
+)
+
+// source completes the record of where in a filing a value was read from
+// with the filing itself. The extractor, if given, replaces the one set
+// when the value was located.
+func source(filing edgar.Filing, extractor string, p *ixbrl.Provenance) *ixbrl.Provenance {
+	if p == nil {
+		p = &ixbrl.Provenance{}
+	}
+	p.Accession = filing.AccessionNumber
+	if filing.PrimaryDocument != "" {
+		p.DocumentURL = filing.URL()
+	}
+	if extractor != "" {
+		p.Extractor = extractor
+	}
+	return p
+}
+
+// sentencePrefix is how much of a sentence is used to find the element
+// it was read from
+const sentencePrefix = 60
+
+// locateSentence returns where a sentence read from the text of nodes is
+// found among them, or just the sentence if the element can't be found,
+// e.g. because the sentence spans several elements
+func locateSentence(nodes []*html.Node, sentence string) *ixbrl.Provenance {
+	prefix := sentence
+	if len(prefix) > sentencePrefix {
+		prefix = prefix[:sentencePrefix]
+		if i := strings.LastIndex(prefix, " "); i > 0 {
+			prefix = prefix[:i]
+		}
+	}
+	for _, node := range nodes {
+		matches := ixbrl.SearchHTML(node, func(text string) string {
+			if strings.Contains(strings.Join(strings.Fields(text), " "), prefix) {
+				return text
+			}
+			return ""
+		})
+		if len(matches) > 0 {
+			return ixbrl.NodeProvenance(matches[0].Node, sentence)
+		}
+	}
+	return &ixbrl.Provenance{Snippet: sentence}
+}
@@ -13,10 +13,11 @@ import (
 	"github.com/ozkatz/cloudzip/pkg/remote"
 	"github.com/ozkatz/cloudzip/pkg/zipfile"
 	"github.com/saranrapjs/labor-leverage/pkg/irsform"
+	"golang.org/x/time/rate"
 )
 
 const (
-	baseURL = "https://apps.irs.gov/pub/epostcard/990/xml"
+	defaultBaseURL = "https://apps.irs.gov/pub/epostcard/990/xml"
 )
 
 type NonProfit struct {
@@ -31,10 +32,76 @@ type NonProfit struct {
 type IRSClient struct {
 	cacheFile string
 	year      string
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
 	NonProfits []NonProfit
 }
 
-func NewIRSClient(cacheDir, year string) (*IRSClient, error) {
+// Option configures an IRSClient created with NewIRSClient
+type Option func(*IRSClient)
+
+// WithBaseURL replaces https://apps.irs.gov/pub/epostcard/990/xml, e.g.
+// to point the client at a mirror or test server
+func WithBaseURL(url string) Option {
+	return func(c *IRSClient) { c.baseURL = strings.TrimSuffix(url, "/") }
+}
+
+// WithHTTPClient sets the HTTP client used to fetch indexes and returns
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *IRSClient) { c.httpClient = client }
+}
+
+// WithTransport sets the transport used to fetch indexes and returns,
+// e.g. to record and replay responses
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *IRSClient) { c.httpClient = &http.Client{Transport: transport} }
+}
+
+// WithUserAgent sets the User-Agent sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *IRSClient) { c.userAgent = userAgent }
+}
+
+// WithRateLimit limits the number of requests made per second
+func WithRateLimit(requestsPerSecond int) Option {
+	return func(c *IRSClient) {
+		if requestsPerSecond <= 0 {
+			return
+		}
+		c.httpClient = &http.Client{
+			Timeout: c.httpClient.Timeout,
+			Transport: &rateLimitedTransport{
+				transport: c.httpClient.Transport,
+				limiter:   rate.NewLimiter(rate.Limit(requestsPerSecond), requestsPerSecond),
+			},
+		}
+	}
+}
+
+// rateLimitedTransport wraps an HTTP transport with rate limiting
+type rateLimitedTransport struct {
+	transport http.RoundTripper
+	limiter   *rate.Limiter
+}
+
+// RoundTrip implements the http.RoundTripper interface with rate limiting
+func (r *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := r.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	transport := r.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req)
+}
+
+// NewIRSClient creates a client for the IRS' 990 filings for a given tax
+// year, loading the year's index of returns from cacheDir or the network.
+// Options are applied in order, so WithRateLimit should come after any
+// WithHTTPClient or WithTransport option it is meant to wrap.
+func NewIRSClient(cacheDir, year string, opts ...Option) (*IRSClient, error) {
 	if cacheDir == "" {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
@@ -51,6 +118,11 @@ func NewIRSClient(cacheDir, year string) (*IRSClient, error) {
 	client := &IRSClient{
 		cacheFile: cacheFile,
 		year:      year,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
 
 	if err := client.loadCSV(); err != nil {
@@ -140,9 +212,13 @@ func (c *IRSClient) parseRecords(records [][]string) error {
 }
 
 func (c *IRSClient) fetchAndCacheCSV() error {
-	indexURL := fmt.Sprintf("https://apps.irs.gov/pub/epostcard/990/xml/%s/index_%s.csv", c.year, c.year)
+	indexURL := fmt.Sprintf("%s/%s/index_%s.csv", c.baseURL, c.year, c.year)
 	
-	resp, err := http.Get(indexURL)
+	req, err := c.newRequest(context.Background(), indexURL)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to fetch CSV: %w", err)
 	}
@@ -184,14 +260,11 @@ func (c *IRSClient) FetchCompany(ein string) ([]byte, error) {
 	}
 
 	batchID := strings.ToUpper(nonprofit.BatchID)
-	zipURL := fmt.Sprintf("%s/%s/%s.zip", baseURL, c.year, batchID)
+	zipURL := fmt.Sprintf("%s/%s/%s.zip", c.baseURL, c.year, batchID)
 	
 	ctx := context.Background()
 	
-	fetcher, err := remote.NewHttpFetcher(zipURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP fetcher: %w", err)
-	}
+	fetcher := &rangeFetcher{client: c, url: zipURL}
 	adapter := zipfile.NewStorageAdapter(ctx, fetcher)
 	parser := zipfile.NewCentralDirectoryParser(adapter)
 	filename := fmt.Sprintf("%s/%s_public.xml", batchID, nonprofit.ObjectID)
@@ -208,3 +281,51 @@ func (c *IRSClient) FetchCompany(ein string) ([]byte, error) {
 
 	return data, nil
 }
+
+func (c *IRSClient) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return req, nil
+}
+
+// rangeFetcher implements cloudzip's remote.Fetcher using HTTP range
+// requests made with the IRSClient's HTTP client, so that only the parts
+// of a ZIP archive needed to read a single return are downloaded.
+type rangeFetcher struct {
+	client *IRSClient
+	url    string
+}
+
+func (f *rangeFetcher) Fetch(ctx context.Context, startOffset *int64, endOffset *int64) (io.ReadCloser, error) {
+	req, err := f.client.newRequest(ctx, f.url)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case startOffset != nil && endOffset != nil:
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", *startOffset, *endOffset))
+	case startOffset != nil:
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", *startOffset))
+	case endOffset != nil:
+		req.Header.Set("Range", fmt.Sprintf("bytes=-%d", *endOffset))
+	}
+
+	resp, err := f.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, remote.ErrDoesNotExist
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
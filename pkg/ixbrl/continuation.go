@@ -0,0 +1,124 @@
+package ixbrl
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Continuation represents ix:continuation elements. Long text facts, such
+// as a note to the financial statements spanning several pages, are split
+// across an ix:nonnumeric and a chain of continuations linked by their
+// continuedAt attributes.
+type Continuation struct {
+	XMLName     xml.Name `xml:"continuation"`
+	ID          string   `xml:"id,attr"`
+	ContinuedAt string   `xml:"continuedat,attr"`
+	// The continuation's own text and markup, less any ix:exclude elements
+	text string
+	html string
+}
+
+// resolveContinuations sets the Content of each NonNumeric to the
+// complete text of the fact: the text of the element itself, including any
+// nested markup, followed by that of each continuation in its chain, less
+// any ix:exclude elements. HTML holds the same fragments as markup.
+//
+// Nodes without an html.Node, as returned by the streaming parser, are
+// expected to hold their own text and markup already.
+func resolveContinuations(nodes []*ParsedNode) {
+	continuations := map[string]*Continuation{}
+	for _, p := range nodes {
+		switch n := p.Struct.(type) {
+		case *NonNumeric:
+			if p.Node != nil {
+				n.Content, n.HTML = fragmentText(withoutExclusions(p.Node))
+			}
+		case *Continuation:
+			if p.Node != nil {
+				n.text, n.html = fragmentText(withoutExclusions(p.Node))
+			}
+			if n.ID != "" {
+				continuations[n.ID] = n
+			}
+		}
+	}
+
+	for _, p := range nodes {
+		nn, ok := p.Struct.(*NonNumeric)
+		if !ok || nn.ContinuedAt == "" {
+			continue
+		}
+		text := []string{}
+		if nn.Content != "" {
+			text = append(text, nn.Content)
+		}
+		markup := nn.HTML
+		// Guard against chains that loop back on themselves
+		seen := map[string]bool{}
+		for next := nn.ContinuedAt; next != "" && !seen[next]; {
+			seen[next] = true
+			c, ok := continuations[next]
+			if !ok {
+				break
+			}
+			if c.text != "" {
+				text = append(text, c.text)
+			}
+			markup += c.html
+			next = c.ContinuedAt
+		}
+		nn.Content = strings.Join(text, "\n")
+		nn.HTML = markup
+	}
+}
+
+// fragmentText returns the text of an element's contents, as displayed,
+// and the contents as HTML.
+func fragmentText(node *html.Node) (string, string) {
+	var markup strings.Builder
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		html.Render(&markup, c)
+	}
+	return HTMLText(node), markup.String()
+}
+
+// fragmentTextFromHTML is fragmentText for an element whose contents are
+// only available as HTML, as when streaming.
+func fragmentTextFromHTML(name, contents string) string {
+	root := &html.Node{Type: html.ElementNode, Data: name}
+	children, err := html.ParseFragment(strings.NewReader(contents), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+	if err != nil {
+		return ""
+	}
+	for _, c := range children {
+		root.AppendChild(c)
+	}
+	return HTMLText(root)
+}
+
+// withoutExclusions returns a detached copy of node's subtree with any
+// ix:exclude elements, which are displayed but not part of a fact's
+// value, left out.
+func withoutExclusions(node *html.Node) *html.Node {
+	clone := &html.Node{
+		Type:      node.Type,
+		DataAtom:  node.DataAtom,
+		Data:      node.Data,
+		Namespace: node.Namespace,
+		Attr:      node.Attr,
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "ix:exclude" {
+			continue
+		}
+		clone.AppendChild(withoutExclusions(c))
+	}
+	return clone
+}
@@ -0,0 +1,279 @@
+package ixbrl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xbrlJSONDocumentType identifies the xBRL-JSON 1.0 format
+const xbrlJSONDocumentType = "https://xbrl.org/2021/xbrl-json"
+
+// wellKnownNamespaces are included in exports whenever their prefix is
+// used, as they may not be declared on a document's root element.
+var wellKnownNamespaces = map[string]string{
+	"xbrli":   "http://www.xbrl.org/2003/instance",
+	"xbrldi":  "http://xbrl.org/2006/xbrldi",
+	"iso4217": "http://www.xbrl.org/2003/iso4217",
+	"cik":     "http://www.sec.gov/CIK",
+}
+
+// exportedFact is a fact reduced to the aspects that the OIM (Open
+// Information Model) and the CSV export describe.
+type exportedFact struct {
+	id         string
+	concept    string
+	value      *string
+	unit       string
+	period     Period
+	entity     Identifier
+	dimensions map[string]string
+	decimals   string
+}
+
+// exportFacts collects the facts amongst nodes, in document order.
+func exportFacts(nodes []*ParsedNode) []exportedFact {
+	var facts []exportedFact
+	for i, p := range nodes {
+		var f exportedFact
+		var context *Context
+		switch n := p.Struct.(type) {
+		case *NonFraction:
+			f = exportedFact{id: n.ID, concept: n.Name, decimals: n.Decimals}
+			if n.Nil != "true" {
+				value := n.Content
+				if v, err := n.ScaledValue(); err == nil {
+					value = strconv.FormatFloat(v, 'f', -1, 64)
+				}
+				f.value = &value
+			}
+			if n.Unit != nil {
+				f.unit = n.Unit.String()
+			}
+			context = n.Context
+		case *NonNumeric:
+			value := n.Content
+			// Text blocks keep their markup
+			if n.Escape == "true" {
+				value = n.HTML
+			}
+			f = exportedFact{id: n.ID, concept: n.Name, value: &value}
+			context = n.Context
+		case *Fraction:
+			// Fractions are exported as "numerator/denominator"
+			f = exportedFact{id: n.ID, concept: n.Name}
+			if n.Nil != "true" {
+				value := strings.TrimSpace(n.Numerator.Content) + "/" + strings.TrimSpace(n.Denominator.Content)
+				if num, denom, err := n.Value(); err == nil {
+					value = strconv.FormatFloat(num, 'f', -1, 64) + "/" + strconv.FormatFloat(denom, 'f', -1, 64)
+				}
+				f.value = &value
+			}
+			if n.Unit != nil {
+				f.unit = n.Unit.String()
+			}
+			context = n.Context
+		default:
+			continue
+		}
+		if context == nil {
+			continue
+		}
+		if f.id == "" {
+			f.id = "f" + strconv.Itoa(i)
+		}
+		f.period = context.Period
+		f.entity = context.Entity.Identifier
+		f.dimensions = context.Dimensions()
+		facts = append(facts, f)
+	}
+	return facts
+}
+
+// oimPeriod formats a period as OIM date-times. End dates are exclusive
+// in the OIM, so e.g. the year ending 2024-12-31 becomes
+// "2024-01-01T00:00:00/2025-01-01T00:00:00".
+func oimPeriod(p Period) string {
+	if instant := strings.TrimSpace(p.Instant); instant != "" {
+		return oimEndDate(instant)
+	}
+	return oimDate(strings.TrimSpace(p.StartDate)) + "/" + oimEndDate(strings.TrimSpace(p.EndDate))
+}
+
+func oimDate(date string) string {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return date
+	}
+	return date + "T00:00:00"
+}
+
+func oimEndDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, 1).Format("2006-01-02") + "T00:00:00"
+}
+
+// oimEntity formats an entity identifier as a QName, using "cik" as the
+// prefix for the SEC's scheme. Other schemes are given the prefixes
+// "scheme", "scheme2" and so on, recorded in schemes by URI so that
+// they can be declared alongside the other namespaces.
+func oimEntity(id Identifier, schemes map[string]string) string {
+	scheme := strings.TrimSpace(id.Scheme)
+	prefix, ok := schemes[scheme]
+	if !ok {
+		prefix = "cik"
+		if scheme != wellKnownNamespaces["cik"] {
+			prefix = "scheme"
+			used := slices.Collect(maps.Values(schemes))
+			for n := 2; slices.Contains(used, prefix); n++ {
+				prefix = "scheme" + strconv.Itoa(n)
+			}
+		}
+		schemes[scheme] = prefix
+	}
+	return prefix + ":" + strings.TrimSpace(id.Content)
+}
+
+type xbrlJSON struct {
+	DocumentInfo xbrlJSONDocumentInfo    `json:"documentInfo"`
+	Facts        map[string]xbrlJSONFact `json:"facts"`
+}
+
+type xbrlJSONDocumentInfo struct {
+	DocumentType string            `json:"documentType"`
+	Namespaces   map[string]string `json:"namespaces"`
+	Taxonomy     []string          `json:"taxonomy,omitempty"`
+}
+
+type xbrlJSONFact struct {
+	Value      *string           `json:"value"`
+	Decimals   *int              `json:"decimals,omitempty"`
+	Dimensions map[string]string `json:"dimensions"`
+}
+
+// WriteJSON writes the facts amongst nodes to w in the xBRL-JSON format
+// of the XBRL Open Information Model. namespaces maps the prefixes used
+// by concepts and dimensions to their URIs, as in Document.Namespaces.
+func WriteJSON(w io.Writer, nodes []*ParsedNode, namespaces map[string]string) error {
+	out := xbrlJSON{
+		DocumentInfo: xbrlJSONDocumentInfo{
+			DocumentType: xbrlJSONDocumentType,
+			Namespaces:   map[string]string{},
+			Taxonomy:     Info(nodes).SchemaRefs,
+		},
+		Facts: map[string]xbrlJSONFact{},
+	}
+
+	usePrefix := func(qname string) {
+		prefix, _, found := strings.Cut(qname, ":")
+		if !found {
+			return
+		}
+		if uri, ok := namespaces[prefix]; ok {
+			out.DocumentInfo.Namespaces[prefix] = uri
+		} else if uri, ok := wellKnownNamespaces[prefix]; ok {
+			out.DocumentInfo.Namespaces[prefix] = uri
+		}
+	}
+
+	// schemes maps the entity identifier schemes in use to their prefixes
+	schemes := map[string]string{}
+	for _, f := range exportFacts(nodes) {
+		dims := map[string]string{
+			"concept": f.concept,
+			"entity":  oimEntity(f.entity, schemes),
+			"period":  oimPeriod(f.period),
+		}
+		usePrefix(f.concept)
+		if f.unit != "" {
+			dims["unit"] = f.unit
+			for _, measure := range strings.Split(f.unit, "/") {
+				usePrefix(measure)
+			}
+		}
+		for axis, member := range f.dimensions {
+			dims[axis] = member
+			usePrefix(axis)
+			usePrefix(member)
+		}
+
+		fact := xbrlJSONFact{Value: f.value, Dimensions: dims}
+		if decimals, err := strconv.Atoi(strings.TrimSpace(f.decimals)); err == nil {
+			fact.Decimals = &decimals
+		}
+		out.Facts[f.id] = fact
+	}
+
+	for scheme, prefix := range schemes {
+		out.DocumentInfo.Namespaces[prefix] = scheme
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("failed to write xBRL-JSON: %w", err)
+	}
+	return nil
+}
+
+// csvHeader lists the columns written by WriteCSV
+var csvHeader = []string{"id", "concept", "value", "unit", "period_start", "period_end", "instant", "dimensions", "decimals"}
+
+// WriteCSV writes the facts amongst nodes to w as CSV, one row per fact
+// in document order. Dimensions are written as "axis=member" pairs,
+// sorted and separated by semicolons.
+func WriteCSV(w io.Writer, nodes []*ParsedNode) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, f := range exportFacts(nodes) {
+		var dims []string
+		for axis, member := range f.dimensions {
+			dims = append(dims, axis+"="+member)
+		}
+		slices.Sort(dims)
+
+		var value string
+		if f.value != nil {
+			value = *f.value
+		}
+		row := []string{
+			f.id,
+			f.concept,
+			value,
+			f.unit,
+			strings.TrimSpace(f.period.StartDate),
+			strings.TrimSpace(f.period.EndDate),
+			strings.TrimSpace(f.period.Instant),
+			strings.Join(dims, ";"),
+			f.decimals,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the document's facts in the xBRL-JSON format
+func (d *Document) WriteJSON(w io.Writer) error {
+	return WriteJSON(w, d.Nodes, d.Namespaces)
+}
+
+// WriteCSV writes the document's facts as CSV
+func (d *Document) WriteCSV(w io.Writer) error {
+	return WriteCSV(w, d.Nodes)
+}
@@ -0,0 +1,284 @@
+package ixbrl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const exportHTML = `<html xmlns:us-gaap="http://fasb.org/us-gaap/2024" xmlns:dei="http://xbrl.sec.gov/dei/2024"><body>
+	<div style="display:none"><ix:header>
+		<ix:references>
+			<link:schemaRef xlink:type="simple" xlink:href="test-20241231.xsd"></link:schemaRef>
+		</ix:references>
+		<ix:resources>
+			<xbrli:context id="FY2024">
+				<xbrli:entity><xbrli:identifier scheme="http://www.sec.gov/CIK">0000000001</xbrli:identifier></xbrli:entity>
+				<xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period>
+			</xbrli:context>
+			<xbrli:context id="FY2024_Segment">
+				<xbrli:entity>
+					<xbrli:identifier scheme="http://www.sec.gov/CIK">0000000001</xbrli:identifier>
+					<xbrli:segment><xbrldi:explicitMember dimension="us-gaap:StatementBusinessSegmentsAxis">test:RetailMember</xbrldi:explicitMember></xbrli:segment>
+				</xbrli:entity>
+				<xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period>
+			</xbrli:context>
+			<xbrli:context id="I2024">
+				<xbrli:entity><xbrli:identifier scheme="http://www.sec.gov/CIK">0000000001</xbrli:identifier></xbrli:entity>
+				<xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period>
+			</xbrli:context>
+			<xbrli:unit id="usd"><xbrli:measure>iso4217:USD</xbrli:measure></xbrli:unit>
+		</ix:resources>
+	</ix:header></div>
+	<p><ix:nonNumeric id="name" name="dei:EntityRegistrantName" contextRef="FY2024">Test Co</ix:nonNumeric></p>
+	<p><ix:nonFraction id="rev" name="us-gaap:Revenues" contextRef="FY2024" unitRef="usd" decimals="-6" scale="6">1,234</ix:nonFraction></p>
+	<p><ix:nonFraction name="us-gaap:Revenues" contextRef="FY2024_Segment" unitRef="usd" decimals="-6" scale="6" sign="-">(5)</ix:nonFraction></p>
+	<p><ix:nonFraction id="cash" name="us-gaap:Cash" contextRef="I2024" unitRef="usd" decimals="INF" xsi:nil="true"></ix:nonFraction></p>
+</body></html>`
+
+func TestWriteJSON(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(exportHTML))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var out struct {
+		DocumentInfo struct {
+			DocumentType string            `json:"documentType"`
+			Namespaces   map[string]string `json:"namespaces"`
+			Taxonomy     []string          `json:"taxonomy"`
+		} `json:"documentInfo"`
+		Facts map[string]struct {
+			Value      *string           `json:"value"`
+			Decimals   *int              `json:"decimals"`
+			Dimensions map[string]string `json:"dimensions"`
+		} `json:"facts"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("WriteJSON produced invalid JSON: %v", err)
+	}
+
+	if out.DocumentInfo.DocumentType != "https://xbrl.org/2021/xbrl-json" {
+		t.Errorf("unexpected documentType %q", out.DocumentInfo.DocumentType)
+	}
+	if out.DocumentInfo.Namespaces["us-gaap"] != "http://fasb.org/us-gaap/2024" {
+		t.Errorf("expected the us-gaap namespace from the document, got %v", out.DocumentInfo.Namespaces)
+	}
+	if out.DocumentInfo.Namespaces["iso4217"] == "" || out.DocumentInfo.Namespaces["cik"] == "" {
+		t.Errorf("expected well-known namespaces for units and entities, got %v", out.DocumentInfo.Namespaces)
+	}
+	if len(out.DocumentInfo.Taxonomy) != 1 || out.DocumentInfo.Taxonomy[0] != "test-20241231.xsd" {
+		t.Errorf("expected taxonomy [test-20241231.xsd], got %v", out.DocumentInfo.Taxonomy)
+	}
+	if len(out.Facts) != 4 {
+		t.Fatalf("expected 4 facts, got %d", len(out.Facts))
+	}
+
+	rev := out.Facts["rev"]
+	if rev.Value == nil || *rev.Value != "1234000000" {
+		t.Errorf("expected rev value 1234000000, got %v", rev.Value)
+	}
+	if rev.Decimals == nil || *rev.Decimals != -6 {
+		t.Errorf("expected rev decimals -6, got %v", rev.Decimals)
+	}
+	expected := map[string]string{
+		"concept": "us-gaap:Revenues",
+		"entity":  "cik:0000000001",
+		"period":  "2024-01-01T00:00:00/2025-01-01T00:00:00",
+		"unit":    "iso4217:USD",
+	}
+	for key, value := range expected {
+		if rev.Dimensions[key] != value {
+			t.Errorf("expected rev %s %q, got %q", key, value, rev.Dimensions[key])
+		}
+	}
+	if len(rev.Dimensions) != len(expected) {
+		t.Errorf("expected no taxonomy dimensions on rev, got %v", rev.Dimensions)
+	}
+
+	// Facts without an ID are keyed by their position in the document
+	var segment bool
+	for id, fact := range out.Facts {
+		if fact.Dimensions["us-gaap:StatementBusinessSegmentsAxis"] == "test:RetailMember" {
+			segment = true
+			if !strings.HasPrefix(id, "f") {
+				t.Errorf("expected a generated ID for the segment fact, got %q", id)
+			}
+			if fact.Value == nil || *fact.Value != "-5000000" {
+				t.Errorf("expected segment value -5000000, got %v", fact.Value)
+			}
+		}
+	}
+	if !segment {
+		t.Errorf("expected a fact for the retail segment")
+	}
+
+	cash := out.Facts["cash"]
+	if cash.Value != nil {
+		t.Errorf("expected a null value for a nil fact, got %q", *cash.Value)
+	}
+	if cash.Decimals != nil {
+		t.Errorf("expected decimals to be omitted for INF, got %d", *cash.Decimals)
+	}
+	if cash.Dimensions["period"] != "2025-01-01T00:00:00" {
+		t.Errorf("expected instant period 2025-01-01T00:00:00, got %q", cash.Dimensions["period"])
+	}
+
+	if out.Facts["name"].Value == nil || *out.Facts["name"].Value != "Test Co" {
+		t.Errorf("expected name value 'Test Co', got %v", out.Facts["name"].Value)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(exportHTML))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("WriteCSV produced invalid CSV: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("expected a header and 4 rows, got %d rows", len(rows))
+	}
+	if strings.Join(rows[0], ",") != "id,concept,value,unit,period_start,period_end,instant,dimensions,decimals" {
+		t.Errorf("unexpected header %v", rows[0])
+	}
+
+	expected := [][]string{
+		{"name", "dei:EntityRegistrantName", "Test Co", "", "2024-01-01", "2024-12-31", "", "", ""},
+		{"rev", "us-gaap:Revenues", "1234000000", "iso4217:USD", "2024-01-01", "2024-12-31", "", "", "-6"},
+		{"", "us-gaap:Revenues", "-5000000", "iso4217:USD", "2024-01-01", "2024-12-31", "", "us-gaap:StatementBusinessSegmentsAxis=test:RetailMember", "-6"},
+		{"cash", "us-gaap:Cash", "", "iso4217:USD", "", "", "2024-12-31", "", "INF"},
+	}
+	for i, want := range expected {
+		got := rows[i+1]
+		for j := range want {
+			// Generated IDs depend on node positions, so only check they're set
+			if j == 0 && want[j] == "" {
+				if got[j] == "" {
+					t.Errorf("row %d: expected a generated ID", i+1)
+				}
+				continue
+			}
+			if got[j] != want[j] {
+				t.Errorf("row %d: expected %s %q, got %q", i+1, rows[0][j], want[j], got[j])
+			}
+		}
+	}
+}
+
+const fractionHTML = `<html xmlns:test="http://example.com/test"><body>
+	<div style="display:none"><ix:header>
+		<ix:resources>
+			<xbrli:context id="I2024">
+				<xbrli:entity><xbrli:identifier scheme="http://www.sec.gov/CIK">0000000001</xbrli:identifier></xbrli:entity>
+				<xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period>
+			</xbrli:context>
+			<xbrli:context id="I2024_LEI">
+				<xbrli:entity><xbrli:identifier scheme="http://standards.iso.org/iso/17442">5493001KJTIIGC8Y1R12</xbrli:identifier></xbrli:entity>
+				<xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period>
+			</xbrli:context>
+			<xbrli:unit id="pure"><xbrli:measure>xbrli:pure</xbrli:measure></xbrli:unit>
+		</ix:resources>
+	</ix:header></div>
+	<p><ix:fraction id="split" name="test:StockSplitRatio" contextRef="I2024" unitRef="pure"><ix:numerator format="ixt:num-dot-decimal">3</ix:numerator> for <ix:denominator format="ixt:num-dot-decimal">2</ix:denominator></ix:fraction></p>
+	<p><ix:fraction id="ratio" name="test:Ratio" contextRef="I2024_LEI" unitRef="pure"><ix:numerator>1,500</ix:numerator>/<ix:denominator scale="3">2</ix:denominator></ix:fraction></p>
+</body></html>`
+
+func TestWriteFraction(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(fractionHTML))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var out struct {
+		DocumentInfo struct {
+			Namespaces map[string]string `json:"namespaces"`
+		} `json:"documentInfo"`
+		Facts map[string]struct {
+			Value      *string           `json:"value"`
+			Dimensions map[string]string `json:"dimensions"`
+		} `json:"facts"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("WriteJSON produced invalid JSON: %v", err)
+	}
+
+	expected := map[string]string{"split": "3/2", "ratio": "1500/2000"}
+	for id, value := range expected {
+		fact := out.Facts[id]
+		if fact.Value == nil || *fact.Value != value {
+			t.Errorf("expected %s value %q, got %v", id, value, fact.Value)
+		}
+	}
+
+	// Entities in schemes other than the SEC's get a declared prefix
+	if out.Facts["split"].Dimensions["entity"] != "cik:0000000001" {
+		t.Errorf("expected entity cik:0000000001, got %q", out.Facts["split"].Dimensions["entity"])
+	}
+	entity := out.Facts["ratio"].Dimensions["entity"]
+	prefix, id, _ := strings.Cut(entity, ":")
+	if id != "5493001KJTIIGC8Y1R12" {
+		t.Errorf("expected the LEI as the entity identifier, got %q", entity)
+	}
+	if out.DocumentInfo.Namespaces[prefix] != "http://standards.iso.org/iso/17442" {
+		t.Errorf("expected prefix %q to be declared for the LEI scheme, got %v", prefix, out.DocumentInfo.Namespaces)
+	}
+	if out.DocumentInfo.Namespaces["cik"] != "http://www.sec.gov/CIK" {
+		t.Errorf("expected the cik prefix to be declared, got %v", out.DocumentInfo.Namespaces)
+	}
+
+	buf.Reset()
+	if err := doc.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("WriteCSV produced invalid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %d rows", len(rows))
+	}
+	for _, row := range rows[1:] {
+		if row[2] != expected[row[0]] {
+			t.Errorf("expected %s value %q, got %q", row[0], expected[row[0]], row[2])
+		}
+	}
+
+	// The streaming parser reads the numerator and denominator too
+	streamed, err := ParseStream(strings.NewReader(fractionHTML))
+	if err != nil {
+		t.Fatalf("ParseStream failed: %v", err)
+	}
+	buf.Reset()
+	if err := streamed.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	streamedRows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("WriteCSV produced invalid CSV: %v", err)
+	}
+	for _, row := range streamedRows[1:] {
+		if row[2] != expected[row[0]] {
+			t.Errorf("expected streamed %s value %q, got %q", row[0], expected[row[0]], row[2])
+		}
+	}
+}
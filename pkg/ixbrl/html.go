@@ -75,8 +75,14 @@ func isInlineNode(node *html.Node) bool {
 		return true
 	}
 	switch node.Data {
-	case "span", "em", "strong", "a", "br":
+	case "span", "em", "strong", "a", "br", "b", "i", "u", "sup", "sub", "small", "font":
 		return true
+	// Facts are tagged inline, within the surrounding text
+	case "ix:nonfraction", "ix:fraction", "ix:exclude":
+		return true
+	// ...except for text blocks, which wrap whole paragraphs or tables
+	case "ix:nonnumeric":
+		return onlyInlineChildren(node)
 	default:
 		return false
 	}
@@ -0,0 +1,114 @@
+package ixbrl
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+)
+
+// References represents ix:references elements, found in the ix:header of
+// a document, which point to the taxonomy schemas defining its concepts.
+type References struct {
+	XMLName    xml.Name    `xml:"references"`
+	SchemaRefs []SchemaRef `xml:"schemaref"`
+}
+
+// SchemaRef represents link:schemaRef elements, each referencing a
+// taxonomy schema, e.g. the filer's own extension schema "aapl-20240928.xsd".
+type SchemaRef struct {
+	XMLName xml.Name `xml:"schemaref"`
+	Href    string   `xml:"href,attr"`
+}
+
+// DocumentInfo holds the cover page facts of a filing, tagged with the
+// SEC's dei (Document and Entity Information) taxonomy. Many of these are
+// in the ix:hidden section of the document's ix:header, and aren't
+// displayed as tagged.
+type DocumentInfo struct {
+	RegistrantName string
+	CIK            string
+	DocumentType   string
+	// PeriodEndDate is the end of the period the document reports on, in
+	// ISO 8601 format
+	PeriodEndDate string
+	// Period is the context of the DocumentPeriodEndDate fact, the whole
+	// period the document reports on, e.g. the fiscal year of a 10-K, or
+	// nil if the document doesn't tag it
+	Period            *Period
+	FiscalYearFocus   string
+	FiscalPeriodFocus string
+	// SharesOutstanding is the total of every class of common stock
+	// outstanding as of SharesOutstandingDate, usually shortly before
+	// the document was filed
+	SharesOutstanding     float64
+	SharesOutstandingDate string
+	// SchemaRefs lists the taxonomy schemas referenced by the document
+	SchemaRefs []string
+}
+
+// Info returns the document's cover page facts
+func (d *Document) Info() DocumentInfo {
+	return Info(d.Nodes)
+}
+
+// Info returns the cover page facts found amongst parsed nodes
+func Info(nodes []*ParsedNode) DocumentInfo {
+	var info DocumentInfo
+	shareContexts := map[string]bool{}
+	for _, p := range nodes {
+		switch n := p.Struct.(type) {
+		case *NonNumeric:
+			value := strings.TrimSpace(n.Content)
+			switch n.Name {
+			case "dei:EntityRegistrantName":
+				setOnce(&info.RegistrantName, value)
+			case "dei:EntityCentralIndexKey":
+				setOnce(&info.CIK, value)
+			case "dei:DocumentType":
+				setOnce(&info.DocumentType, value)
+			case "dei:DocumentFiscalYearFocus":
+				setOnce(&info.FiscalYearFocus, value)
+			case "dei:DocumentFiscalPeriodFocus":
+				setOnce(&info.FiscalPeriodFocus, value)
+			case "dei:DocumentPeriodEndDate":
+				date, err := ParseDate(n.Format, value)
+				if err != nil && n.Context != nil {
+					// The fact's context covers the same period
+					date = strings.TrimSpace(n.Context.Period.EndDate + n.Context.Period.Instant)
+				}
+				setOnce(&info.PeriodEndDate, date)
+				if info.Period == nil && n.Context != nil {
+					period := n.Context.Period
+					info.Period = &period
+				}
+			}
+		case *NonFraction:
+			// Each class of stock is reported in its own context, and the
+			// same fact may be tagged more than once
+			if n.Name != "dei:EntityCommonStockSharesOutstanding" || shareContexts[n.ContextRef] {
+				continue
+			}
+			value, err := n.ScaledValue()
+			if err != nil {
+				continue
+			}
+			shareContexts[n.ContextRef] = true
+			info.SharesOutstanding += value
+			if n.Context != nil {
+				setOnce(&info.SharesOutstandingDate, strings.TrimSpace(n.Context.Period.Instant))
+			}
+		case *SchemaRef:
+			// Each schemaRef is also parsed as part of its ix:references
+			if !slices.Contains(info.SchemaRefs, n.Href) {
+				info.SchemaRefs = append(info.SchemaRefs, n.Href)
+			}
+		}
+	}
+	return info
+}
+
+func setOnce(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
@@ -0,0 +1,101 @@
+package ixbrl
+
+import (
+	"strings"
+	"testing"
+)
+
+const coverPageHTML = `<html><body>
+	<div style="display:none"><ix:header>
+		<ix:hidden>
+			<ix:nonNumeric name="dei:DocumentFiscalYearFocus" contextRef="c-1">2024</ix:nonNumeric>
+			<ix:nonNumeric name="dei:DocumentFiscalPeriodFocus" contextRef="c-1">FY</ix:nonNumeric>
+			<ix:nonNumeric name="dei:EntityCentralIndexKey" contextRef="c-1">0000320193</ix:nonNumeric>
+		</ix:hidden>
+		<ix:references>
+			<link:schemaRef xlink:type="simple" xlink:href="aapl-20240928.xsd"></link:schemaRef>
+		</ix:references>
+		<ix:resources>
+			<xbrli:context id="c-1"><xbrli:period><xbrli:startDate>2023-10-01</xbrli:startDate><xbrli:endDate>2024-09-28</xbrli:endDate></xbrli:period></xbrli:context>
+			<xbrli:context id="c-shares"><xbrli:period><xbrli:instant>2024-10-18</xbrli:instant></xbrli:period></xbrli:context>
+			<xbrli:context id="c-shares-b">
+				<xbrli:entity><xbrli:segment><xbrldi:explicitMember dimension="us-gaap:StatementClassOfStockAxis">test:ClassBMember</xbrldi:explicitMember></xbrli:segment></xbrli:entity>
+				<xbrli:period><xbrli:instant>2024-10-18</xbrli:instant></xbrli:period>
+			</xbrli:context>
+			<xbrli:unit id="shares"><xbrli:measure>xbrli:shares</xbrli:measure></xbrli:unit>
+		</ix:resources>
+	</ix:header></div>
+	<p>FORM <ix:nonNumeric name="dei:DocumentType" contextRef="c-1">10-K</ix:nonNumeric></p>
+	<p>For the fiscal year ended <ix:nonNumeric name="dei:DocumentPeriodEndDate" contextRef="c-1" format="ixt:date-monthname-day-year-en">September 28, 2024</ix:nonNumeric></p>
+	<p><ix:nonNumeric name="dei:EntityRegistrantName" contextRef="c-1">Apple Inc.</ix:nonNumeric></p>
+	<p><ix:nonFraction name="dei:EntityCommonStockSharesOutstanding" contextRef="c-shares" unitRef="shares" scale="3">15,115,823</ix:nonFraction> shares of Class A and
+	<ix:nonFraction name="dei:EntityCommonStockSharesOutstanding" contextRef="c-shares-b" unitRef="shares" scale="0">1,000</ix:nonFraction> shares of Class B</p>
+</body></html>`
+
+func TestInfo(t *testing.T) {
+	dom, err := ParseDocument(strings.NewReader(coverPageHTML))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	streamed, err := ParseStream(strings.NewReader(coverPageHTML))
+	if err != nil {
+		t.Fatalf("ParseStream failed: %v", err)
+	}
+
+	for name, doc := range map[string]*Document{"dom": dom, "stream": streamed} {
+		info := doc.Info()
+		if info.RegistrantName != "Apple Inc." {
+			t.Errorf("%s: expected RegistrantName 'Apple Inc.', got %q", name, info.RegistrantName)
+		}
+		if info.CIK != "0000320193" {
+			t.Errorf("%s: expected CIK '0000320193', got %q", name, info.CIK)
+		}
+		if info.DocumentType != "10-K" {
+			t.Errorf("%s: expected DocumentType '10-K', got %q", name, info.DocumentType)
+		}
+		if info.PeriodEndDate != "2024-09-28" {
+			t.Errorf("%s: expected PeriodEndDate '2024-09-28', got %q", name, info.PeriodEndDate)
+		}
+		if info.Period == nil || !info.Period.Equal(Period{StartDate: "2023-10-01", EndDate: "2024-09-28"}) {
+			t.Errorf("%s: expected Period 2023-10-01 to 2024-09-28, got %+v", name, info.Period)
+		}
+		if info.FiscalYearFocus != "2024" || info.FiscalPeriodFocus != "FY" {
+			t.Errorf("%s: expected fiscal focus 2024 FY, got %q %q", name, info.FiscalYearFocus, info.FiscalPeriodFocus)
+		}
+		if info.SharesOutstanding != 15115824000 {
+			t.Errorf("%s: expected SharesOutstanding 15115824000, got %f", name, info.SharesOutstanding)
+		}
+		if info.SharesOutstandingDate != "2024-10-18" {
+			t.Errorf("%s: expected SharesOutstandingDate '2024-10-18', got %q", name, info.SharesOutstandingDate)
+		}
+		if len(info.SchemaRefs) != 1 || info.SchemaRefs[0] != "aapl-20240928.xsd" {
+			t.Errorf("%s: expected SchemaRefs [aapl-20240928.xsd], got %v", name, info.SchemaRefs)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		format   string
+		content  string
+		expected string
+	}{
+		{"ixt:date-monthname-day-year-en", "December  31, 2024", "2024-12-31"},
+		{"ixt:datemonthdayyearen", "Sep 28, 2024", "2024-09-28"},
+		{"ixt:num-dot-decimal", "1,234", ""},
+		{"ixt:date-month-day-year", "12/31/2024", "2024-12-31"},
+		{"", "2024-12-31", "2024-12-31"},
+	}
+	for _, test := range tests {
+		date, err := ParseDate(test.format, test.content)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("ParseDate(%q, %q) expected an error, got %q", test.format, test.content, date)
+			}
+			continue
+		}
+		if err != nil || date != test.expected {
+			t.Errorf("ParseDate(%q, %q) = %q (%v), expected %q", test.format, test.content, date, err, test.expected)
+		}
+	}
+}
@@ -17,6 +17,9 @@ var registry = nodeRegistry{
 	"ix:nonfraction": func() interface{} { return &NonFraction{} },
 	"ix:nonnumeric":  func() interface{} { return &NonNumeric{} },
 	"ix:fraction":    func() interface{} { return &Fraction{} },
+	"ix:continuation": func() interface{} { return &Continuation{} },
+	"ix:references":   func() interface{} { return &References{} },
+	"link:schemaref":  func() interface{} { return &SchemaRef{} },
 	"xbrli:context":  func() interface{} { return &Context{} },
 	"xbrli:unit":     func() interface{} { return &Unit{} },
 }
@@ -28,56 +31,96 @@ type ParsedNode struct {
 	Type   string
 }
 
+// Document is a parsed iXBRL document: its iXBRL nodes, the XHTML
+// document they were found in, and indexes of its contexts and units.
+type Document struct {
+	Nodes []*ParsedNode
+	Root  *html.Node
+	// Namespaces maps the prefixes declared on the root element to their
+	// URIs, e.g. "us-gaap" => "http://fasb.org/us-gaap/2024"
+	Namespaces map[string]string
+	contexts   map[string]*Context
+	units    map[string]*Unit
+}
+
+// Context returns the context with the given ID
+func (d *Document) Context(id string) (*Context, bool) {
+	c, ok := d.contexts[id]
+	return c, ok
+}
+
+// Unit returns the unit with the given ID
+func (d *Document) Unit(id string) (*Unit, bool) {
+	u, ok := d.units[id]
+	return u, ok
+}
+
 // Parse parses an XHTML document and returns parsed iXBRL nodes,
 // alongside the parsed XHTML document.
 func Parse(r io.Reader) ([]*ParsedNode, *html.Node, error) {
-	doc, err := html.Parse(r)
+	doc, err := ParseDocument(r)
 	if err != nil {
-		return nil, doc, err
+		return nil, nil, err
+	}
+	return doc.Nodes, doc.Root, nil
+}
+
+// ParseDocument parses an XHTML document, linking each fact to its
+// context and unit.
+func ParseDocument(r io.Reader) (*Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Root: root, Namespaces: map[string]string{}}
+	for n := root.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type == html.ElementNode && n.Data == "html" {
+			addNamespaces(doc.Namespaces, n.Attr)
+		}
+	}
+	collectAndParseNodes(root, &doc.Nodes)
+	doc.link()
+	return doc, nil
+}
+
+// addNamespaces records any xmlns:prefix declarations amongst attrs
+func addNamespaces(namespaces map[string]string, attrs []html.Attribute) {
+	for _, a := range attrs {
+		if prefix, ok := strings.CutPrefix(a.Key, "xmlns:"); ok {
+			namespaces[prefix] = a.Val
+		}
 	}
+}
 
-	var parsedNodes []*ParsedNode
-	collectAndParseNodes(doc, &parsedNodes)
-	contexts := getContexts(parsedNodes)
-	for i, p := range parsedNodes {
-		node := p.Struct
-		switch n := node.(type) {
+// link indexes the document's contexts and units, and links each fact to
+// its own, then resolves the text of continued facts.
+func (doc *Document) link() {
+	doc.contexts = map[string]*Context{}
+	doc.units = map[string]*Unit{}
+	for _, p := range doc.Nodes {
+		switch n := p.Struct.(type) {
+		case *Context:
+			doc.contexts[n.ID] = n
+		case *Unit:
+			doc.units[n.ID] = n
+		}
+	}
+	for _, p := range doc.Nodes {
+		switch n := p.Struct.(type) {
 		case *NonFraction:
-			{
-				for _, c := range contexts {
-					if c.ID == n.ContextRef {
-						n.Context = c
-						p.Struct = n
-						parsedNodes[i] = p
-					}
-				}
-			}
+			n.Context = doc.contexts[n.ContextRef]
+			n.Unit = doc.units[n.UnitRef]
 		case *NonNumeric:
-			{
-				for _, c := range contexts {
-					if c.ID == n.ContextRef {
-						n.Context = c
-						p.Struct = n
-						parsedNodes[i] = p
-					}
-				}
-			}
+			n.Context = doc.contexts[n.ContextRef]
 		case *Fraction:
-			{
-				for _, c := range contexts {
-					if c.ID == n.ContextRef {
-						n.Context = c
-						p.Struct = n
-						parsedNodes[i] = p
-					}
-				}
-			}
+			n.Context = doc.contexts[n.ContextRef]
+			n.Unit = doc.units[n.UnitRef]
 		}
 	}
-	return parsedNodes, doc, nil
+	resolveContinuations(doc.Nodes)
 }
 
-
 // collectAndParseNodes recursively traverses the HTML tree and
 // collects/parses nodes with colons, as a fuzzy test for
 // whether or not they are likely to correspond to iXBRL tags.
@@ -120,10 +163,18 @@ type NonFraction struct {
 	Name       string   `xml:"name,attr"`
 	Format     string   `xml:"format,attr"`
 	Scale      string   `xml:"scale,attr"`
+	// Sign is "-" for negative values, which are displayed without a minus
+	Sign       string   `xml:"sign,attr"`
+	Nil        string   `xml:"nil,attr"`
 	ID         string   `xml:"id,attr"`
 	Content    string   `xml:",chardata"`
 	ContextRef string   `xml:"contextref,attr"`
 	Context    *Context
+	Unit       *Unit `json:",omitempty"`
+	// Labels are set by Document.ApplyTaxonomy
+	Labels *ConceptLabels `xml:"-" json:",omitempty"`
+	// Source records where the value was extracted from, where known
+	Source *Provenance `xml:"-" json:",omitempty"`
 }
 
 func (nf *NonFraction) scale() float64 {
@@ -135,44 +186,121 @@ func (nf *NonFraction) scale() float64 {
 }
 
 
-func (nf *NonFraction) number() float64 {
-	// Remove commas and parse
-	cleanContent := strings.ReplaceAll(nf.Content, ",", "")
-	value, err := strconv.ParseFloat(cleanContent, 64)
+// Value returns the fact's value before scaling, applying its format
+// and sign. Values displayed in parentheses or with a leading minus are
+// read as negative.
+func (nf *NonFraction) Value() (float64, error) {
+	if nf.Nil == "true" {
+		return 0, ErrNilValue
+	}
+	content := strings.TrimSpace(nf.Content)
+	negative := nf.Sign == "-"
+	if strings.HasPrefix(content, "(") && strings.HasSuffix(content, ")") {
+		content = strings.TrimSpace(content[1 : len(content)-1])
+		negative = true
+	}
+	// Values converted from other sources, e.g. IRS returns, use a minus
+	if strings.HasPrefix(content, "-") && !isDash(content) {
+		content = content[1:]
+		negative = !negative
+	}
+	value, err := ParseNumber(nf.Format, content)
 	if err != nil {
-		return 0
+		return 0, fmt.Errorf("failed to parse %s: %w", nf.Name, err)
 	}
-	return value
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// ScaledValue applies the scale factor (stored as a power of 10) to the
+// fact's Value. An error is returned where the value can't be read
+// according to the fact's format.
+func (nf *NonFraction) ScaledValue() (float64, error) {
+	value, err := nf.Value()
+	if err != nil {
+		return 0, err
+	}
+	return nf.scale() * value, nil
 }
 
 // Applies the scale factor (stored as a power of 10)
-// to the non-fractional value.
+// to the non-fractional value. Values that can't be read are 0,
+// which ScaledValue tells apart by returning an error.
 func (nf *NonFraction) ScaledNumber() float64 {
-	return nf.scale() * nf.number();
+	value, _ := nf.ScaledValue()
+	return value
 }
 
 // NonNumeric represents ix:nonnumeric elements. These are textual or non-numeric facts,
 // such as company names, descriptions, or other qualitative information.
+//
+// Content is the fact's complete plain text, including any nested markup
+// and continuations, and HTML the same as an HTML fragment.
 type NonNumeric struct {
-	XMLName    xml.Name `xml:"nonnumeric"`
-	Name       string   `xml:"name,attr"`
-	Format     string   `xml:"format,attr"`
-	ID         string   `xml:"id,attr"`
-	Content    string   `xml:",chardata"`
-	ContextRef string   `xml:"contextref,attr"`
-	Context    *Context
+	XMLName     xml.Name `xml:"nonnumeric"`
+	Name        string   `xml:"name,attr"`
+	Format      string   `xml:"format,attr"`
+	ID          string   `xml:"id,attr"`
+	Content     string   `xml:",chardata"`
+	HTML        string   `xml:"-"`
+	ContinuedAt string   `xml:"continuedat,attr"`
+	Escape      string   `xml:"escape,attr"`
+	ContextRef  string   `xml:"contextref,attr"`
+	Context     *Context
+	Labels      *ConceptLabels `xml:"-" json:",omitempty"`
 }
 
 // Fraction represents ix:fraction elements. These are numeric facts reported as fractions
 // with separate numerator and denominator components (e.g., 22.5/77.5).
 type Fraction struct {
-	XMLName    xml.Name `xml:"fraction"`
-	UnitRef    string   `xml:"unitref,attr"`
-	Name       string   `xml:"name,attr"`
-	ID         string   `xml:"id,attr"`
-	Content    string   `xml:",chardata"`
-	ContextRef string   `xml:"contextref,attr"`
-	Context    *Context
+	XMLName     xml.Name     `xml:"fraction"`
+	UnitRef     string       `xml:"unitref,attr"`
+	Name        string       `xml:"name,attr"`
+	ID          string       `xml:"id,attr"`
+	Nil         string       `xml:"nil,attr"`
+	Content     string       `xml:",chardata"`
+	Numerator   FractionTerm `xml:"numerator"`
+	Denominator FractionTerm `xml:"denominator"`
+	ContextRef  string       `xml:"contextref,attr"`
+	Context     *Context
+	Unit        *Unit          `json:",omitempty"`
+	Labels      *ConceptLabels `xml:"-" json:",omitempty"`
+}
+
+// FractionTerm represents the ix:numerator and ix:denominator elements of
+// an ix:fraction, which are formatted, scaled and signed like ix:nonFraction
+// values.
+type FractionTerm struct {
+	Format  string `xml:"format,attr"`
+	Scale   string `xml:"scale,attr"`
+	Sign    string `xml:"sign,attr"`
+	Content string `xml:",chardata"`
+}
+
+// Value returns the term's value, applying its format, scale and sign
+func (t FractionTerm) Value() (float64, error) {
+	nf := NonFraction{Format: t.Format, Scale: t.Scale, Sign: t.Sign, Content: t.Content}
+	return nf.ScaledValue()
+}
+
+// Value returns the fraction's numerator and denominator. An error is
+// returned for nil fractions, or where either term can't be read
+// according to its format.
+func (f *Fraction) Value() (numerator, denominator float64, err error) {
+	if f.Nil == "true" {
+		return 0, 0, ErrNilValue
+	}
+	numerator, err = f.Numerator.Value()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse the numerator of %s: %w", f.Name, err)
+	}
+	denominator, err = f.Denominator.Value()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse the denominator of %s: %w", f.Name, err)
+	}
+	return numerator, denominator, nil
 }
 
 // Context represents xbrli:context elements. These provide dimensional context for facts,
@@ -240,11 +368,44 @@ type TypedMember struct {
 }
 
 // Unit represents xbrli:unit elements. These define the unit of measurement for numeric facts
-// (e.g., USD, shares, square feet, percentages).
+// (e.g., USD, shares, square feet, percentages). Ratios such as earnings per share are
+// defined by a Divide instead of a single Measure.
 type Unit struct {
 	XMLName xml.Name `xml:"unit"`
 	ID      string   `xml:"id,attr"`
 	Measure Measure  `xml:"measure"`
+	Divide  *Divide  `xml:"divide"`
+}
+
+// Divide represents xbrli:divide elements, defining a unit as a ratio of two measures.
+type Divide struct {
+	XMLName     xml.Name `xml:"divide"`
+	Numerator   Measure  `xml:"unitnumerator>measure"`
+	Denominator Measure  `xml:"unitdenominator>measure"`
+}
+
+// String returns the unit's measure, e.g. "iso4217:USD", or for ratios
+// its numerator and denominator, e.g. "iso4217:USD/xbrli:shares".
+func (u *Unit) String() string {
+	if u.Divide != nil {
+		return strings.TrimSpace(u.Divide.Numerator.Content) + "/" + strings.TrimSpace(u.Divide.Denominator.Content)
+	}
+	return strings.TrimSpace(u.Measure.Content)
+}
+
+// IsMonetary reports whether the unit is a currency, e.g. "iso4217:USD"
+func (u *Unit) IsMonetary() bool {
+	return u.Divide == nil && strings.HasPrefix(strings.TrimSpace(u.Measure.Content), "iso4217:")
+}
+
+// IsShares reports whether the unit counts shares
+func (u *Unit) IsShares() bool {
+	return u.Divide == nil && strings.TrimSpace(u.Measure.Content) == "xbrli:shares"
+}
+
+// IsPure reports whether the unit is a pure number, e.g. a percentage
+func (u *Unit) IsPure() bool {
+	return u.Divide == nil && strings.TrimSpace(u.Measure.Content) == "xbrli:pure"
 }
 
 // Measure represents xbrli:measure elements. These specify the actual measurement unit
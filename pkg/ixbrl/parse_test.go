@@ -221,6 +221,100 @@ func TestParseNonNumeric(t *testing.T) {
 	}
 }
 
+func TestParseNonNumericContinuation(t *testing.T) {
+	html := `<html><body>
+		<ix:nonnumeric contextref="c-1" name="us-gaap:HumanCapitalTextBlock" escape="true" continuedat="f-2" id="f-1">
+			<div><span>Human Capital</span></div>
+			<p>As of September 28, 2024, the Company had approximately 164,000 full-time equivalent employees.</p>
+		</ix:nonnumeric>
+		<div>Apple Inc. | 2024 Form 10-K | <ix:exclude>7</ix:exclude></div>
+		<ix:continuation id="f-2" continuedat="f-3">
+			<p>The Company believes it has a <b>culture</b> of inclusion.</p>
+			<ix:exclude><p>Page 8</p></ix:exclude>
+		</ix:continuation>
+		<ix:continuation id="f-3"><p>Workplace practices and policies</p></ix:continuation>
+	</body></html>`
+	nodes, _, err := Parse(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	nn := Search(nodes, func(n *NonNumeric) bool { return n.ID == "f-1" })
+	if nn == nil {
+		t.Fatal("Expected to find NonNumeric f-1")
+	}
+	expected := "Human Capital\nAs of September 28, 2024, the Company had approximately 164,000 full-time equivalent employees.\nThe Company believes it has a culture of inclusion.\nWorkplace practices and policies"
+	if nn.Content != expected {
+		t.Errorf("Expected Content %q, got %q", expected, nn.Content)
+	}
+	if strings.Contains(nn.Content, "Page 8") || strings.Contains(nn.HTML, "Page 8") {
+		t.Errorf("Expected ix:exclude content to be left out, got %q", nn.Content)
+	}
+	if !strings.Contains(nn.HTML, "<b>culture</b>") {
+		t.Errorf("Expected HTML to keep nested markup, got %q", nn.HTML)
+	}
+}
+
+func TestParseNonNumericContinuationLoop(t *testing.T) {
+	html := `<html><body>
+		<ix:nonnumeric contextref="c-1" name="test" continuedat="f-2" id="f-1">One</ix:nonnumeric>
+		<ix:continuation id="f-2" continuedat="f-2">Two</ix:continuation>
+	</body></html>`
+	nodes, _, err := Parse(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	nn := Search(nodes, func(n *NonNumeric) bool { return true })
+	if nn.Content != "One\nTwo" {
+		t.Errorf("Expected Content %q, got %q", "One\nTwo", nn.Content)
+	}
+}
+
+func TestParseDocumentUnits(t *testing.T) {
+	html := `<html><body>
+		<xbrli:context id="c-1"><xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period></xbrli:context>
+		<xbrli:unit id="usd"><xbrli:measure>iso4217:USD</xbrli:measure></xbrli:unit>
+		<xbrli:unit id="shares"><xbrli:measure>xbrli:shares</xbrli:measure></xbrli:unit>
+		<xbrli:unit id="usdPerShare"><xbrli:divide>
+			<xbrli:unitNumerator><xbrli:measure>iso4217:USD</xbrli:measure></xbrli:unitNumerator>
+			<xbrli:unitDenominator><xbrli:measure>xbrli:shares</xbrli:measure></xbrli:unitDenominator>
+		</xbrli:divide></xbrli:unit>
+		<ix:nonFraction name="us-gaap:Cash" contextRef="c-1" unitRef="usd">100</ix:nonFraction>
+		<ix:nonFraction name="dei:EntityCommonStockSharesOutstanding" contextRef="c-1" unitRef="shares">5</ix:nonFraction>
+		<ix:nonFraction name="us-gaap:EarningsPerShareBasic" contextRef="c-1" unitRef="usdPerShare">1.25</ix:nonFraction>
+	</body></html>`
+	doc, err := ParseDocument(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	if c, ok := doc.Context("c-1"); !ok || c.Period.Instant != "2024-12-31" {
+		t.Errorf("Expected context c-1 to be found, got %v", c)
+	}
+	if _, ok := doc.Context("c-2"); ok {
+		t.Error("Expected context c-2 not to be found")
+	}
+	if u, ok := doc.Unit("usdPerShare"); !ok || u.String() != "iso4217:USD/xbrli:shares" {
+		t.Errorf("Expected unit usdPerShare to be found, got %v", u)
+	}
+
+	facts := FilterByType(doc.Nodes, func(*NonFraction) bool { return true })
+	if len(facts) != 3 {
+		t.Fatalf("Expected 3 facts, got %d", len(facts))
+	}
+	for _, nf := range facts {
+		if nf.Context == nil || nf.Context.ID != "c-1" {
+			t.Errorf("Expected %s to be linked to context c-1", nf.Name)
+		}
+		if nf.Unit == nil || nf.Unit.ID != nf.UnitRef {
+			t.Errorf("Expected %s to be linked to unit %s", nf.Name, nf.UnitRef)
+		}
+	}
+	if !facts[0].Unit.IsMonetary() || !facts[1].Unit.IsShares() || facts[2].Unit.IsMonetary() {
+		t.Error("Expected units to be USD, shares and USD per share")
+	}
+}
+
 func TestFilterByType(t *testing.T) {
 	html := `<html><body>
 		<ix:nonfraction unitref="usd" contextref="c-1">1000</ix:nonfraction>
@@ -0,0 +1,175 @@
+package ixbrl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Provenance records where a value was extracted from, so that it can be
+// checked against the filing before it's quoted: which filing and
+// document, where in the document, and by which extractor.
+type Provenance struct {
+	// Accession is the accession number of the filing
+	Accession string `json:"accession,omitempty"`
+	// DocumentURL is the URL of the document within the filing
+	DocumentURL string `json:"document_url,omitempty"`
+	// FactID is the id of the iXBRL fact the value was read from
+	FactID string `json:"fact_id,omitempty"`
+	// ElementID is the id of the element the value was read from, or of
+	// its nearest ancestor with one
+	ElementID string `json:"element_id,omitempty"`
+	// XPath locates the element the value was read from in the document
+	XPath string `json:"xpath,omitempty"`
+	// Snippet is the text the value was read from
+	Snippet string `json:"snippet,omitempty"`
+	// Extractor names what read the value, e.g. "ixbrl:us-gaap:NetIncomeLoss"
+	// or "text:ceo-pay-ratio"
+	Extractor string `json:"extractor"`
+}
+
+// maxSnippet caps the text kept for a provenance record
+const maxSnippet = 500
+
+// maxFragmentWords is how many of a snippet's words link to it, as
+// browsers only highlight text fragments that match exactly
+const maxFragmentWords = 8
+
+// URL returns a link to the exact spot in the document the value was
+// read from: the iXBRL fact by its id where there is one, or else a text
+// fragment highlighting the start of the snippet, or else the nearest
+// element with an id. It returns "" if the document's URL isn't known.
+func (p *Provenance) URL() string {
+	if p == nil || p.DocumentURL == "" {
+		return ""
+	}
+	switch {
+	case p.FactID != "":
+		return p.DocumentURL + "#" + url.PathEscape(p.FactID)
+	case p.Snippet != "":
+		return p.DocumentURL + "#:~:text=" + textFragment(p.Snippet)
+	case p.ElementID != "":
+		return p.DocumentURL + "#" + url.PathEscape(p.ElementID)
+	}
+	return p.DocumentURL
+}
+
+// textFragment encodes the first words of a snippet as the text of a URL
+// text fragment, where dashes, commas and ampersands are also reserved
+func textFragment(snippet string) string {
+	words := strings.Fields(snippet)
+	if len(words) > maxFragmentWords {
+		words = words[:maxFragmentWords]
+	}
+	encoded := url.PathEscape(strings.Join(words, " "))
+	return strings.NewReplacer("-", "%2D", ",", "%2C", "&", "%26").Replace(encoded)
+}
+
+// NodeProvenance returns the location of an HTML node in its document,
+// with its text as the snippet unless one is given. Text nodes, as found
+// by SearchHTML, are located by the element they're in.
+func NodeProvenance(n *html.Node, snippet string) *Provenance {
+	for n.Type != html.ElementNode && n.Parent != nil {
+		n = n.Parent
+	}
+	if snippet == "" {
+		snippet = HTMLText(n)
+	}
+	p := &Provenance{
+		XPath:   XPath(n),
+		Snippet: truncateSnippet(strings.Join(strings.Fields(snippet), " ")),
+	}
+	for a := n; a != nil; a = a.Parent {
+		if id := attr(a, "id"); id != "" {
+			p.ElementID = id
+			break
+		}
+	}
+	return p
+}
+
+// FactProvenance returns the location of a fact parsed from the document,
+// e.g. a *NonFraction returned by Undimensioned, or nil if it isn't one
+// of the document's facts.
+func (d *Document) FactProvenance(fact interface{}) *Provenance {
+	for _, node := range d.Nodes {
+		if node.Struct != fact {
+			continue
+		}
+		p := NodeProvenance(node.Node, "")
+		switch f := fact.(type) {
+		case *NonFraction:
+			p.FactID = f.ID
+			p.Extractor = "ixbrl:" + f.Name
+		case *NonNumeric:
+			p.FactID = f.ID
+			p.Extractor = "ixbrl:" + f.Name
+		case *Fraction:
+			p.FactID = f.ID
+			p.Extractor = "ixbrl:" + f.Name
+		}
+		return p
+	}
+	return nil
+}
+
+// XPath returns an absolute XPath to an element, e.g.
+// "/html/body/div[2]/p[3]", counting only siblings of the same name.
+// Text nodes are located by their parent element.
+func XPath(n *html.Node) string {
+	for n != nil && n.Type != html.ElementNode {
+		n = n.Parent
+	}
+	var steps []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		index, count := 0, 0
+		if n.Parent != nil {
+			for s := n.Parent.FirstChild; s != nil; s = s.NextSibling {
+				if s.Type == html.ElementNode && s.Data == n.Data {
+					count++
+					if s == n {
+						index = count
+					}
+				}
+			}
+		}
+		step := n.Data
+		if count > 1 {
+			step = fmt.Sprintf("%s[%d]", n.Data, index)
+		}
+		steps = append(steps, step)
+	}
+	var b strings.Builder
+	for i := len(steps) - 1; i >= 0; i-- {
+		b.WriteString("/")
+		b.WriteString(steps[i])
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func truncateSnippet(s string) string {
+	if len(s) <= maxSnippet {
+		return s
+	}
+	// Cut at a word, or else at least not within a multi-byte character
+	if i := strings.LastIndex(s[:maxSnippet], " "); i > 0 {
+		return s[:i] + "…"
+	}
+	n := maxSnippet
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
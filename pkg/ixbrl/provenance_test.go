@@ -0,0 +1,82 @@
+package ixbrl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestXPath(t *testing.T) {
+	root := parseHTML(t, `<html><body><div><p>One</p></div><div id="second"><p>Two</p><p>Three <b>bold</b></p></div></body></html>`)
+	matches := SearchHTML(root, func(text string) string {
+		if strings.Contains(text, "Three") {
+			return text
+		}
+		return ""
+	})
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	if got := XPath(matches[0].Node); got != "/html/body/div[2]/p[2]" {
+		t.Errorf("Expected /html/body/div[2]/p[2], got %q", got)
+	}
+
+	p := NodeProvenance(matches[0].Node, "")
+	if p.ElementID != "second" {
+		t.Errorf("Expected the nearest id, got %q", p.ElementID)
+	}
+	if p.Snippet != "Three bold" {
+		t.Errorf("Expected the node's text as snippet, got %q", p.Snippet)
+	}
+}
+
+func TestProvenanceURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *Provenance
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"no document", &Provenance{FactID: "f-1"}, ""},
+		{"fact", &Provenance{DocumentURL: "https://www.sec.gov/doc.htm", FactID: "f-1", Snippet: "100"}, "https://www.sec.gov/doc.htm#f-1"},
+		{
+			"snippet",
+			&Provenance{DocumentURL: "https://www.sec.gov/doc.htm", ElementID: "item1", Snippet: "As of December 31, we had 1,500 full-time employees in our U.S. offices."},
+			"https://www.sec.gov/doc.htm#:~:text=As%20of%20December%2031%2C%20we%20had%201%2C500%20full%2Dtime",
+		},
+		{"element", &Provenance{DocumentURL: "https://www.sec.gov/doc.htm", ElementID: "item1"}, "https://www.sec.gov/doc.htm#item1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.URL(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFactProvenance(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(`<html><body>
+		<xbrli:context id="c-1"><xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period></xbrli:context>
+		<p>Net income was $<ix:nonFraction id="f-7" name="us-gaap:NetIncomeLoss" contextRef="c-1" unitRef="usd">1,000</ix:nonFraction>.</p>
+	</body></html>`))
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+	nf := doc.Undimensioned("us-gaap:NetIncomeLoss", nil)
+	if nf == nil {
+		t.Fatal("Expected to find net income")
+	}
+	p := doc.FactProvenance(nf)
+	if p == nil {
+		t.Fatal("Expected the fact to be located")
+	}
+	if p.FactID != "f-7" || p.Extractor != "ixbrl:us-gaap:NetIncomeLoss" || p.Snippet != "1,000" {
+		t.Errorf("Unexpected provenance: %+v", p)
+	}
+	if !strings.HasSuffix(p.XPath, "/p/ix:nonfraction") {
+		t.Errorf("Expected the fact's XPath, got %q", p.XPath)
+	}
+	if doc.FactProvenance(&NonFraction{}) != nil {
+		t.Error("Expected no provenance for a fact from elsewhere")
+	}
+}
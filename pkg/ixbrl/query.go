@@ -0,0 +1,87 @@
+package ixbrl
+
+import "strings"
+
+// Dimensions returns the explicit members of the context's segment, keyed
+// by axis, e.g. "srt:ProductOrServiceAxis" => "us-gaap:ServiceMember".
+func (c *Context) Dimensions() map[string]string {
+	dims := map[string]string{}
+	for _, m := range c.Entity.Segment.ExplicitMembers {
+		dims[strings.TrimSpace(m.Dimension)] = strings.TrimSpace(m.Content)
+	}
+	for _, m := range c.Entity.Segment.TypedMembers {
+		dims[strings.TrimSpace(m.Dimension)] = strings.TrimSpace(m.Content)
+	}
+	return dims
+}
+
+// IsDimensioned reports whether the context breaks a value down along
+// some axis, e.g. by segment, product or share class, rather than
+// describing the entity as a whole.
+func (c *Context) IsDimensioned() bool {
+	return len(c.Entity.Segment.ExplicitMembers) > 0 || len(c.Entity.Segment.TypedMembers) > 0
+}
+
+// Equal reports whether two periods cover the same dates
+func (p Period) Equal(o Period) bool {
+	return strings.TrimSpace(p.Instant) == strings.TrimSpace(o.Instant) &&
+		strings.TrimSpace(p.StartDate) == strings.TrimSpace(o.StartDate) &&
+		strings.TrimSpace(p.EndDate) == strings.TrimSpace(o.EndDate)
+}
+
+// FactQuery selects numeric facts by concept, period and dimensions.
+//
+// Facts are only matched when their context has exactly the given
+// dimensions, so the zero value of Dimensions selects values for the
+// entity as a whole, rather than for one of its segments or share classes.
+type FactQuery struct {
+	// Name is the qualified concept name, e.g. "us-gaap:NetIncomeLoss"
+	Name string
+	// Period, if set, restricts the query to facts for that period
+	Period *Period
+	// Dimensions maps each required axis to its member
+	Dimensions map[string]string
+}
+
+// Matches reports whether a fact satisfies the query
+func (q FactQuery) Matches(nf *NonFraction) bool {
+	if nf.Name != q.Name || nf.Context == nil {
+		return false
+	}
+	if q.Period != nil && !nf.Context.Period.Equal(*q.Period) {
+		return false
+	}
+	dims := nf.Context.Dimensions()
+	if len(dims) != len(q.Dimensions) {
+		return false
+	}
+	for axis, member := range q.Dimensions {
+		if dims[axis] != member {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryFacts returns the numeric facts matching a query, in document order.
+func QueryFacts(nodes []*ParsedNode, q FactQuery) []*NonFraction {
+	return FilterByType(nodes, q.Matches)
+}
+
+// QueryFact returns the first numeric fact matching a query, or nil.
+func QueryFact(nodes []*ParsedNode, q FactQuery) *NonFraction {
+	return Search(nodes, q.Matches)
+}
+
+// Query returns the document's numeric facts matching a query, in document order.
+func (d *Document) Query(q FactQuery) []*NonFraction {
+	return QueryFacts(d.Nodes, q)
+}
+
+// Undimensioned returns the value of a concept for the entity as a whole,
+// ignoring any breakdown by segment or share class. If period is nil, the
+// first such value in the document is returned, which is usually the
+// current period's.
+func (d *Document) Undimensioned(name string, period *Period) *NonFraction {
+	return QueryFact(d.Nodes, FactQuery{Name: name, Period: period})
+}
@@ -0,0 +1,59 @@
+package ixbrl
+
+import (
+	"strings"
+	"testing"
+)
+
+const segmentedHTML = `<html><body>
+	<xbrli:context id="c-2024">
+		<xbrli:entity><xbrli:identifier scheme="http://www.sec.gov/CIK">0000320193</xbrli:identifier></xbrli:entity>
+		<xbrli:period><xbrli:startDate>2023-10-01</xbrli:startDate><xbrli:endDate>2024-09-28</xbrli:endDate></xbrli:period>
+	</xbrli:context>
+	<xbrli:context id="c-2024-services">
+		<xbrli:entity>
+			<xbrli:identifier scheme="http://www.sec.gov/CIK">0000320193</xbrli:identifier>
+			<xbrli:segment><xbrldi:explicitMember dimension="srt:ProductOrServiceAxis">us-gaap:ServiceMember</xbrldi:explicitMember></xbrli:segment>
+		</xbrli:entity>
+		<xbrli:period><xbrli:startDate>2023-10-01</xbrli:startDate><xbrli:endDate>2024-09-28</xbrli:endDate></xbrli:period>
+	</xbrli:context>
+	<xbrli:context id="c-2023">
+		<xbrli:entity><xbrli:identifier scheme="http://www.sec.gov/CIK">0000320193</xbrli:identifier></xbrli:entity>
+		<xbrli:period><xbrli:startDate>2022-09-25</xbrli:startDate><xbrli:endDate>2023-09-30</xbrli:endDate></xbrli:period>
+	</xbrli:context>
+	<p>Services revenue was <ix:nonFraction name="us-gaap:Revenues" contextRef="c-2024-services" unitRef="usd" scale="6">96,169</ix:nonFraction></p>
+	<p>Total revenue was <ix:nonFraction name="us-gaap:Revenues" contextRef="c-2024" unitRef="usd" scale="6">391,035</ix:nonFraction>
+	compared to <ix:nonFraction name="us-gaap:Revenues" contextRef="c-2023" unitRef="usd" scale="6">383,285</ix:nonFraction></p>
+</body></html>`
+
+func TestQueryFacts(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(segmentedHTML))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	total := doc.Undimensioned("us-gaap:Revenues", nil)
+	if total == nil || total.Content != "391,035" {
+		t.Errorf("Expected the undimensioned total, got %v", total)
+	}
+
+	prior := doc.Undimensioned("us-gaap:Revenues", &Period{StartDate: "2022-09-25", EndDate: "2023-09-30"})
+	if prior == nil || prior.Content != "383,285" {
+		t.Errorf("Expected the prior year's total, got %v", prior)
+	}
+
+	services := doc.Query(FactQuery{
+		Name:       "us-gaap:Revenues",
+		Dimensions: map[string]string{"srt:ProductOrServiceAxis": "us-gaap:ServiceMember"},
+	})
+	if len(services) != 1 || services[0].Content != "96,169" {
+		t.Errorf("Expected the services segment value, got %v", services)
+	}
+	if !services[0].Context.IsDimensioned() {
+		t.Error("Expected the services context to be dimensioned")
+	}
+
+	if all := doc.Query(FactQuery{Name: "us-gaap:Revenues"}); len(all) != 2 {
+		t.Errorf("Expected 2 undimensioned values, got %d", len(all))
+	}
+}
@@ -0,0 +1,336 @@
+package ixbrl
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// IDs of commonly used sections of annual reports and proxy statements.
+// Items of other forms are identified the same way, e.g. "item-9a".
+const (
+	SectionBusiness              = "item-1"
+	SectionRiskFactors           = "item-1a"
+	SectionMarket                = "item-5"
+	SectionMDA                   = "item-7"
+	SectionFinancialStatements   = "item-8"
+	SectionExecutiveCompensation = "item-11"
+
+	SectionCompensationDiscussion = "compensation-discussion-and-analysis"
+	SectionSummaryCompensation    = "summary-compensation-table"
+	SectionPayRatio               = "pay-ratio"
+	SectionPayVersusPerformance   = "pay-versus-performance"
+)
+
+// Section is a part of a filing, like "Item 1A. Risk Factors" of an
+// annual report or the "Summary Compensation Table" of a proxy statement.
+type Section struct {
+	// ID identifies the section, e.g. "item-1a" or "pay-ratio"
+	ID string
+	// Title is the text of the section's heading
+	Title string
+	// Nodes are the block-level elements making up the section, starting
+	// with its heading
+	Nodes []*html.Node
+}
+
+// Text returns the section's text, as displayed by [HTMLText]
+func (s Section) Text() string {
+	return HTMLText(s.Nodes...)
+}
+
+// SearchHTML searches the section like [SearchHTML]
+func (s Section) SearchHTML(predicate func(text string) string) []Match {
+	var matches []Match
+	for _, node := range s.Nodes {
+		matches = append(matches, SearchHTML(node, predicate)...)
+	}
+	return matches
+}
+
+// FindTables searches the section like [FindTables]
+func (s Section) FindTables(predicate func(text string) bool) []*html.Node {
+	var tables []*html.Node
+	for _, node := range s.Nodes {
+		tables = append(tables, FindTables(node, predicate)...)
+	}
+	return tables
+}
+
+// maxHeadingLength is the longest text that's considered a heading
+const maxHeadingLength = 200
+
+var (
+	itemHeadingRegex = regexp.MustCompile(`(?i)^item\s*(\d{1,2}[a-d]?)\s*[.:\x{2014}\x{2013}-]?\s*(.*)$`)
+	partHeadingRegex = regexp.MustCompile(`(?i)^part\s+(iv|i{1,3})\b`)
+)
+
+// proxyHeadings identifies the sections of proxy statements by the
+// beginning of their headings. Sections that extractors don't look for
+// are listed too, so that they end the sections before them.
+var proxyHeadings = []struct {
+	id    string
+	regex *regexp.Regexp
+}{
+	{SectionCompensationDiscussion, regexp.MustCompile(`(?i)^compensation\s+discussion\s+(and|&)\s+analysis\b`)},
+	{SectionSummaryCompensation, regexp.MustCompile(`(?i)^summary\s+compensation\s+table\b`)},
+	{"grants-of-plan-based-awards", regexp.MustCompile(`(?i)^grants\s+of\s+plan[\s-]based\s+awards\b`)},
+	{"outstanding-equity-awards", regexp.MustCompile(`(?i)^outstanding\s+equity\s+awards\b`)},
+	{"option-exercises-and-stock-vested", regexp.MustCompile(`(?i)^(option\s+exercises\s+and\s+)?stock\s+vested\b`)},
+	{"pension-benefits", regexp.MustCompile(`(?i)^pension\s+benefits\b`)},
+	{"nonqualified-deferred-compensation", regexp.MustCompile(`(?i)^non-?qualified\s+deferred\s+compensation\b`)},
+	{"potential-payments", regexp.MustCompile(`(?i)^potential\s+payments\s+upon\b`)},
+	{SectionPayRatio, regexp.MustCompile(`(?i)^(ceo\s+)?pay\s+ratio\b`)},
+	{SectionPayVersusPerformance, regexp.MustCompile(`(?i)^pay\s+(versus|vs\.?)\s+performance\b`)},
+	{"director-compensation", regexp.MustCompile(`(?i)^(non-employee\s+)?director\s+compensation\b`)},
+	{"equity-compensation-plan-information", regexp.MustCompile(`(?i)^equity\s+compensation\s+plan\s+information\b`)},
+	{"security-ownership", regexp.MustCompile(`(?i)^security\s+ownership\b`)},
+}
+
+// classifyHeading returns the ID of the section that a heading starts,
+// or false if text isn't a recognized heading.
+func classifyHeading(text string) (string, bool) {
+	if text == "" || len(text) > maxHeadingLength {
+		return "", false
+	}
+	if m := itemHeadingRegex.FindStringSubmatch(text); m != nil {
+		// Cross-references like "Item 7 of this report", and 8-K items
+		// like "Item 2.05", aren't 10-K item headings
+		first, _ := utf8.DecodeRuneInString(m[2])
+		if unicode.IsLower(first) || unicode.IsDigit(first) {
+			return "", false
+		}
+		return "item-" + strings.ToLower(m[1]), true
+	}
+	if m := partHeadingRegex.FindStringSubmatch(text); m != nil {
+		return "part-" + strings.ToLower(m[1]), true
+	}
+	for _, heading := range proxyHeadings {
+		if heading.regex.MatchString(text) {
+			return heading.id, true
+		}
+	}
+	return "", false
+}
+
+// block is a paragraph-like element of a document: an element with only
+// inline content, or a table.
+type block struct {
+	node *html.Node
+	text string
+	// toc is true for blocks linking to elsewhere in the document, like
+	// the entries of a table of contents
+	toc bool
+}
+
+// documentBlocks flattens a document into its blocks, in order. It also
+// returns the index of the block at or after each element with an ID,
+// so that links from the table of contents can be followed.
+func documentBlocks(root *html.Node) ([]block, map[string]int) {
+	var blocks []block
+	anchors := map[string]int{}
+
+	var addAnchors func(n *html.Node, index int)
+	addAnchors = func(n *html.Node, index int) {
+		if n.Type == html.ElementNode {
+			for _, attr := range n.Attr {
+				if (attr.Key == "id" || attr.Key == "name") && attr.Val != "" {
+					if _, ok := anchors[attr.Val]; !ok {
+						anchors[attr.Val] = index
+					}
+				}
+			}
+		}
+	}
+	var addDescendantAnchors func(n *html.Node, index int)
+	addDescendantAnchors = func(n *html.Node, index int) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			addAnchors(child, index)
+			addDescendantAnchors(child, index)
+		}
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			addAnchors(child, len(blocks))
+			if child.Data == "table" || onlyInlineChildren(child) {
+				text := strings.Join(strings.Fields(HTMLText(child)), " ")
+				if text == "" {
+					continue
+				}
+				addDescendantAnchors(child, len(blocks))
+				blocks = append(blocks, block{node: child, text: text, toc: hasInternalLink(child)})
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+	return blocks, anchors
+}
+
+func hasInternalLink(n *html.Node) bool {
+	if n.Type == html.ElementNode && n.Data == "a" {
+		for _, attr := range n.Attr {
+			if attr.Key == "href" && strings.HasPrefix(attr.Val, "#") {
+				return true
+			}
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if hasInternalLink(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// tableOfContents follows the links of a table of contents, returning
+// the section IDs of the blocks they link to. An entry is recognized by
+// the text of its link, or of the table row the link is in.
+func tableOfContents(root *html.Node, anchors map[string]int) map[int]string {
+	linked := map[int]string{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, attr := range n.Attr {
+				target, ok := strings.CutPrefix(attr.Val, "#")
+				if attr.Key != "href" || !ok {
+					continue
+				}
+				index, ok := anchors[target]
+				if !ok {
+					continue
+				}
+				id, ok := classifyHeading(strings.Join(strings.Fields(HTMLText(n)), " "))
+				if !ok {
+					if row := enclosingRow(n); row != nil {
+						id, ok = classifyHeading(strings.Join(strings.Fields(HTMLText(row)), " "))
+					}
+				}
+				if _, seen := linked[index]; ok && !seen {
+					linked[index] = id
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	return linked
+}
+
+func enclosingRow(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "tr" {
+			return p
+		}
+	}
+	return nil
+}
+
+// Sections splits an EDGAR HTML document into its sections: the Items
+// and Parts of annual and quarterly reports, and the sections of proxy
+// statements that cover executive compensation.
+//
+// Headings are found by following the links of the document's table of
+// contents, or else by their text. Where a heading's text appears more
+// than once, as in a table of contents without links or in page headers,
+// the occurrence followed by the most text is taken to be the heading.
+// Sections run until the next heading, and are returned in order.
+func Sections(root *html.Node) []Section {
+	blocks, anchors := documentBlocks(root)
+	linked := tableOfContents(root, anchors)
+
+	type candidate struct {
+		index  int
+		id     string
+		linked bool
+		length int
+	}
+	var candidates []candidate
+	for i, b := range blocks {
+		if id, ok := linked[i]; ok {
+			candidates = append(candidates, candidate{index: i, id: id, linked: true})
+			continue
+		}
+		if b.toc {
+			continue
+		}
+		if id, ok := classifyHeading(b.text); ok {
+			candidates = append(candidates, candidate{index: i, id: id})
+		}
+	}
+
+	// Measure the text following each candidate, up to the next one
+	for i := range candidates {
+		end := len(blocks)
+		if i+1 < len(candidates) {
+			end = candidates[i+1].index
+		}
+		for _, b := range blocks[candidates[i].index+1 : end] {
+			candidates[i].length += len(b.text)
+		}
+	}
+
+	chosen := map[string]candidate{}
+	for _, c := range candidates {
+		best, ok := chosen[c.id]
+		switch {
+		case !ok:
+		case c.linked && !best.linked:
+		case c.linked == best.linked && c.length > best.length:
+		default:
+			continue
+		}
+		chosen[c.id] = c
+	}
+
+	var headings []candidate
+	for _, c := range candidates {
+		if chosen[c.id] == c {
+			headings = append(headings, c)
+		}
+	}
+
+	sections := make([]Section, 0, len(headings))
+	for i, heading := range headings {
+		end := len(blocks)
+		if i+1 < len(headings) {
+			end = headings[i+1].index
+		}
+		section := Section{ID: heading.id, Title: blocks[heading.index].text}
+		for _, b := range blocks[heading.index:end] {
+			section.Nodes = append(section.Nodes, b.node)
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// FindSection returns the section with the given ID
+func FindSection(sections []Section, id string) (Section, bool) {
+	for _, s := range sections {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// Sections splits the document into its sections, see [Sections]. Only
+// documents parsed with [ParseDocument] have the HTML needed to do so.
+func (d *Document) Sections() []Section {
+	if d.Root == nil {
+		return nil
+	}
+	return Sections(d.Root)
+}
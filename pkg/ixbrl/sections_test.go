@@ -0,0 +1,151 @@
+package ixbrl
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const annualReportHTML = `<html><body>
+<p>TABLE OF CONTENTS</p>
+<table>
+	<tr><td><a href="#part1">PART I</a></td><td></td></tr>
+	<tr><td>Item 1.</td><td><a href="#item1">Business</a></td><td>1</td></tr>
+	<tr><td>Item 1A.</td><td><a href="#item1a">Risk Factors</a></td><td>5</td></tr>
+	<tr><td><a href="#part2">PART II</a></td><td></td></tr>
+	<tr><td>Item 7.</td><td><a href="#item7">Management's Discussion and Analysis</a></td><td>20</td></tr>
+</table>
+<div id="part1"></div>
+<p><b>PART I</b></p>
+<div id="item1"><p><b>Item 1. Business</b></p></div>
+<p>We make widgets. Our risks are discussed in Item 1A of this report.</p>
+<p>As of December 31, 2024, we had 1,500 full-time employees.</p>
+<p id="item1a"><b>Item 1A. Risk Factors</b></p>
+<p>Widgets may go out of fashion.</p>
+<p>Item 7 of this report discusses our results.</p>
+<p id="part2"><b>PART II</b></p>
+<p><b>Item 5. Market for Registrant's Common Equity</b></p>
+<p>Our stock trades on the NASDAQ.</p>
+<table id="item7"><tr><td>Item 7.</td><td>Management's Discussion and Analysis</td></tr></table>
+<p>Revenue grew in 2024.</p>
+</body></html>`
+
+func parseHTML(t *testing.T, content string) *html.Node {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+	return doc
+}
+
+func TestSections(t *testing.T) {
+	sections := Sections(parseHTML(t, annualReportHTML))
+
+	var ids []string
+	for _, s := range sections {
+		ids = append(ids, s.ID)
+	}
+	expected := "part-i,item-1,item-1a,part-ii,item-5,item-7"
+	if strings.Join(ids, ",") != expected {
+		t.Fatalf("expected sections %s, got %s", expected, strings.Join(ids, ","))
+	}
+
+	business, ok := FindSection(sections, SectionBusiness)
+	if !ok {
+		t.Fatal("expected to find Item 1")
+	}
+	if business.Title != "Item 1. Business" {
+		t.Errorf("expected title 'Item 1. Business', got %q", business.Title)
+	}
+	text := business.Text()
+	if !strings.Contains(text, "1,500 full-time employees") || strings.Contains(text, "fashion") {
+		t.Errorf("expected Item 1 to end where Item 1A starts, got:\n%s", text)
+	}
+
+	risks, _ := FindSection(sections, SectionRiskFactors)
+	if !strings.Contains(risks.Text(), "Item 7 of this report") {
+		t.Errorf("expected cross-references to stay within their section, got:\n%s", risks.Text())
+	}
+
+	mda, ok := FindSection(sections, SectionMDA)
+	if !ok {
+		t.Fatal("expected to find Item 7")
+	}
+	if mda.Title != "Item 7. Management's Discussion and Analysis" {
+		t.Errorf("unexpected Item 7 title %q", mda.Title)
+	}
+	if !strings.Contains(mda.Text(), "Revenue grew") {
+		t.Errorf("expected Item 7 text, got:\n%s", mda.Text())
+	}
+
+	matches := business.SearchHTML(func(text string) string {
+		if strings.Contains(text, "employees") {
+			return text
+		}
+		return ""
+	})
+	if len(matches) != 1 {
+		t.Errorf("expected one match within Item 1, got %d", len(matches))
+	}
+}
+
+func TestSectionsProxyStatement(t *testing.T) {
+	doc := parseHTML(t, `<html><body>
+		<p>Summary Compensation Table ........ 45</p>
+		<p>CEO Pay Ratio ........ 60</p>
+		<p><b>Compensation Discussion and Analysis</b></p>
+		<p>Our executives are paid well.</p>
+		<p><b>Summary Compensation Table—2024, 2023 and 2022</b></p>
+		<table><tr><td>Name</td><td>Salary ($)</td></tr><tr><td>Jane Doe</td><td>1,000,000</td></tr></table>
+		<p><b>Grants of Plan-Based Awards</b></p>
+		<p>Jane Doe was granted stock.</p>
+		<p><b>Pay Versus Performance</b></p>
+		<p>Compensation actually paid tracked shareholder return.</p>
+	</body></html>`)
+	sections := Sections(doc)
+
+	summary, ok := FindSection(sections, SectionSummaryCompensation)
+	if !ok {
+		t.Fatal("expected to find the Summary Compensation Table")
+	}
+	if summary.Title != "Summary Compensation Table—2024, 2023 and 2022" {
+		t.Errorf("expected the heading rather than the table of contents, got %q", summary.Title)
+	}
+	tables := summary.FindTables(func(text string) bool { return strings.Contains(text, "Salary") })
+	if len(tables) != 1 {
+		t.Errorf("expected one table in the section, got %d", len(tables))
+	}
+	if strings.Contains(summary.Text(), "granted stock") {
+		t.Errorf("expected the section to end at the next heading, got:\n%s", summary.Text())
+	}
+
+	if _, ok := FindSection(sections, SectionPayVersusPerformance); !ok {
+		t.Errorf("expected to find Pay Versus Performance")
+	}
+}
+
+func TestSectionsFixture(t *testing.T) {
+	f, err := os.Open("../facts/fixtures/apple.html")
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	defer f.Close()
+	doc, err := ParseDocument(f)
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	ratio, ok := FindSection(doc.Sections(), SectionPayRatio)
+	if !ok {
+		t.Fatal("expected to find the CEO pay ratio section")
+	}
+	if ratio.Title != "CEO Pay Ratio—2024" {
+		t.Errorf("unexpected title %q", ratio.Title)
+	}
+	if !strings.Contains(ratio.Text(), "650 to 1") {
+		t.Errorf("expected the pay ratio text, got:\n%s", ratio.Text())
+	}
+}
@@ -0,0 +1,241 @@
+package ixbrl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Decoder reads iXBRL facts, contexts and units from an XHTML document as
+// a stream of tokens, without building the document's DOM. It uses much
+// less memory than Parse for large filings, for callers that don't need
+// to search the document's HTML.
+type Decoder struct {
+	z *html.Tokenizer
+	// open is the stack of elements open within the outermost capture
+	open []string
+	// excluded holds the stack indexes of open ix:exclude elements
+	excluded []int
+	captures []*capture
+	pending  []*ParsedNode
+	err      error
+	// Namespaces maps the prefixes declared on the root element to their URIs
+	Namespaces map[string]string
+}
+
+// capture accumulates a registered element while it is being read
+type capture struct {
+	name string
+	// level is the element's index in the Decoder's open stack
+	level int
+	// xml is the element re-serialized as XML, for xml.Unmarshal
+	xml strings.Builder
+	// textual elements only keep their start tag in xml, and their
+	// contents, less any ix:exclude elements, as HTML in contents
+	textual  bool
+	contents strings.Builder
+}
+
+// voidElements never have end tags, so aren't pushed on the open stack
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// NewDecoder creates a Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{z: html.NewTokenizer(r), Namespaces: map[string]string{}}
+}
+
+// Next returns the next registered iXBRL element, such as a
+// *NonFraction or *Context, once its end tag has been read. Elements
+// nested within another, e.g. a fact within a text block, are returned
+// before the element containing them. The returned ParsedNode has no
+// html.Node, and facts are not linked to their contexts or units, which
+// may appear later in the document; ParseStream does both. At the end of
+// the document, Next returns io.EOF.
+func (d *Decoder) Next() (*ParsedNode, error) {
+	for len(d.pending) == 0 {
+		if d.err != nil {
+			return nil, d.err
+		}
+		d.readToken()
+	}
+	p := d.pending[0]
+	d.pending = d.pending[1:]
+	return p, nil
+}
+
+func (d *Decoder) readToken() {
+	switch tt := d.z.Next(); tt {
+	case html.ErrorToken:
+		d.err = d.z.Err()
+		if d.err == nil {
+			d.err = io.EOF
+		}
+	case html.TextToken:
+		if len(d.captures) > 0 {
+			// Text is within every element on the stack
+			d.write(html.EscapeString(string(d.z.Text())), len(d.open))
+		}
+	case html.StartTagToken, html.SelfClosingTagToken:
+		name, attrs := d.readTag()
+		if name == "html" {
+			addNamespaces(d.Namespaces, attrs)
+		}
+		d.startElement(name, attrs, tt == html.SelfClosingTagToken || voidElements[name])
+	case html.EndTagToken:
+		if len(d.captures) > 0 {
+			name, _ := d.z.TagName()
+			d.endElement(string(name))
+		}
+	}
+}
+
+// readTag reads the current tag's name and attributes
+func (d *Decoder) readTag() (string, []html.Attribute) {
+	rawName, hasAttr := d.z.TagName()
+	name := string(rawName)
+	var attrs []html.Attribute
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = d.z.TagAttr()
+		attrs = append(attrs, html.Attribute{Key: string(key), Val: string(val)})
+	}
+	return name, attrs
+}
+
+func (d *Decoder) startElement(name string, attrs []html.Attribute, selfClosing bool) {
+	_, registered := registry[name]
+	if !registered && len(d.captures) == 0 {
+		return
+	}
+
+	level := len(d.open)
+	if !selfClosing {
+		d.open = append(d.open, name)
+		if name == "ix:exclude" {
+			d.excluded = append(d.excluded, level)
+		}
+	}
+	d.write(startTag(name, attrs, selfClosing), level)
+
+	if registered {
+		c := &capture{
+			name:    name,
+			level:   level,
+			textual: name == "ix:nonnumeric" || name == "ix:continuation",
+		}
+		c.xml.WriteString(startTag(name, attrs, false))
+		d.captures = append(d.captures, c)
+		if selfClosing {
+			d.finish(c)
+		}
+	}
+}
+
+func (d *Decoder) endElement(name string) {
+	// Find the element being closed, closing any left open within it, as
+	// HTML allows e.g. paragraphs without end tags
+	i := len(d.open) - 1
+	for i >= 0 && d.open[i] != name {
+		i--
+	}
+	if i < 0 {
+		return
+	}
+	for j := len(d.open) - 1; j >= i; j-- {
+		d.write("</"+d.open[j]+">", j)
+		d.open = d.open[:j]
+		if n := len(d.excluded); n > 0 && d.excluded[n-1] == j {
+			d.excluded = d.excluded[:n-1]
+		}
+		if n := len(d.captures); n > 0 && d.captures[n-1].level == j {
+			d.finish(d.captures[n-1])
+		}
+	}
+}
+
+// write appends the markup of an element at the given level of the open
+// stack to every capture containing it. Markup within an ix:exclude
+// element is left out of the contents of textual elements outside it.
+func (d *Decoder) write(markup string, level int) {
+	for _, c := range d.captures {
+		if level <= c.level {
+			continue
+		}
+		if !c.textual {
+			c.xml.WriteString(markup)
+		} else if !d.isExcluded(c, level) {
+			c.contents.WriteString(markup)
+		}
+	}
+}
+
+func (d *Decoder) isExcluded(c *capture, level int) bool {
+	for _, e := range d.excluded {
+		if e > c.level && e <= level {
+			return true
+		}
+	}
+	return false
+}
+
+// finish unmarshals a completed capture, and queues it to be returned
+func (d *Decoder) finish(c *capture) {
+	d.captures = d.captures[:len(d.captures)-1]
+	c.xml.WriteString("</" + c.name + ">")
+
+	s := registry[c.name]()
+	if err := xml.Unmarshal([]byte(c.xml.String()), s); err != nil {
+		fmt.Printf("error conforming xml: %v\n", err)
+		return
+	}
+	switch n := s.(type) {
+	case *NonNumeric:
+		n.HTML = c.contents.String()
+		n.Content = fragmentTextFromHTML(c.name, n.HTML)
+	case *Continuation:
+		n.html = c.contents.String()
+		n.text = fragmentTextFromHTML(c.name, n.html)
+	}
+	d.pending = append(d.pending, &ParsedNode{Struct: s, Type: c.name})
+}
+
+// startTag serializes a start tag as XML
+func startTag(name string, attrs []html.Attribute, selfClosing bool) string {
+	var b strings.Builder
+	b.WriteString("<" + name)
+	for _, a := range attrs {
+		b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+	}
+	if selfClosing {
+		b.WriteString("/")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// ParseStream reads every registered iXBRL element from r with a Decoder,
+// and links facts to their contexts and units as Parse does. The returned
+// Document has no Root, and its ParsedNodes no html.Node.
+func ParseStream(r io.Reader) (*Document, error) {
+	d := NewDecoder(r)
+	doc := &Document{Namespaces: d.Namespaces}
+	for {
+		p, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		doc.Nodes = append(doc.Nodes, p)
+	}
+	doc.link()
+	return doc, nil
+}
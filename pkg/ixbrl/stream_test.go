@@ -0,0 +1,156 @@
+package ixbrl
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const streamHTML = `<html><body>
+	<div style="display:none"><ix:header><ix:resources>
+		<xbrli:context id="c-1"><xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period></xbrli:context>
+		<xbrli:unit id="usd"><xbrli:measure>iso4217:USD</xbrli:measure></xbrli:unit>
+	</ix:resources></ix:header></div>
+	<ix:nonNumeric name="us-gaap:HumanCapitalTextBlock" contextRef="c-1" continuedAt="f-2" id="f-1">
+		<p>We had <ix:nonFraction name="dei:EntityNumberOfEmployees" contextRef="c-1" unitRef="usd" scale="0">5,900</ix:nonFraction> employees.</p>
+		<p>Line breaks<br> are allowed.</p>
+	</ix:nonNumeric>
+	<p>Page 7 <ix:exclude>of 100</ix:exclude></p>
+	<ix:continuation id="f-2"><p>The <b>rest</b> of the disclosure.</p><ix:exclude><p>Page 8</p></ix:exclude></ix:continuation>
+	<p>Net income: $<ix:nonFraction name="us-gaap:NetIncomeLoss" contextRef="c-1" unitRef="usd" scale="6" sign="-" format="ixt:num-dot-decimal">1,234</ix:nonFraction> million</p>
+</body></html>`
+
+func TestParseStream(t *testing.T) {
+	for name, input := range map[string]string{"stream": streamHTML, "segmented": segmentedHTML} {
+		t.Run(name, func(t *testing.T) {
+			doc, err := ParseDocument(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("ParseDocument failed: %v", err)
+			}
+			streamed, err := ParseStream(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("ParseStream failed: %v", err)
+			}
+			compareRegistered(t, doc.Nodes, streamed.Nodes)
+		})
+	}
+}
+
+func TestParseStreamText(t *testing.T) {
+	doc, err := ParseStream(strings.NewReader(streamHTML))
+	if err != nil {
+		t.Fatalf("ParseStream failed: %v", err)
+	}
+	nn := Search(doc.Nodes, func(n *NonNumeric) bool { return n.ID == "f-1" })
+	if nn == nil {
+		t.Fatal("Expected to find NonNumeric f-1")
+	}
+	expected := "We had 5,900 employees.\nLine breaks are allowed.\nThe rest of the disclosure."
+	if nn.Content != expected {
+		t.Errorf("Expected Content %q, got %q", expected, nn.Content)
+	}
+	if nn.Context == nil || nn.Context.ID != "c-1" {
+		t.Error("Expected NonNumeric to be linked to its context")
+	}
+
+	nf := Search(doc.Nodes, func(n *NonFraction) bool { return n.Name == "us-gaap:NetIncomeLoss" })
+	if nf == nil || nf.Unit == nil || !nf.Unit.IsMonetary() {
+		t.Fatal("Expected NetIncomeLoss to be linked to its unit")
+	}
+	if value, err := nf.ScaledValue(); err != nil || value != -1234000000 {
+		t.Errorf("Expected -1234000000, got %f (%v)", value, err)
+	}
+}
+
+func TestParseStreamUnclosedElements(t *testing.T) {
+	input := `<ix:nonNumeric name="a" contextRef="c-1"><p>One<p>Two</ix:nonNumeric>
+		<ix:nonFraction name="b" contextRef="c-1">3</ix:nonFraction>`
+	doc, err := ParseStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseStream failed: %v", err)
+	}
+	nn := Search(doc.Nodes, func(n *NonNumeric) bool { return true })
+	if nn == nil || nn.Content != "One\nTwo" {
+		t.Errorf("Expected the text block to end at its end tag, got %+v", nn)
+	}
+	if nf := Search(doc.Nodes, func(n *NonFraction) bool { return n.Name == "b" }); nf == nil {
+		t.Error("Expected to find the fact following the text block")
+	}
+}
+
+// compareRegistered checks that both parsers found the same registered
+// elements, ignoring the html.Node only the DOM parser has.
+func compareRegistered(t *testing.T, expected, actual []*ParsedNode) {
+	t.Helper()
+	var want []any
+	for _, p := range expected {
+		if p.Struct != nil {
+			want = append(want, p.Struct)
+		}
+	}
+	if len(want) != len(actual) {
+		t.Fatalf("Expected %d registered elements, got %d", len(want), len(actual))
+	}
+	// Nested elements are streamed before their parents, so match by content
+	for _, w := range want {
+		found := false
+		for _, a := range actual {
+			if reflect.DeepEqual(w, a.Struct) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Streamed elements are missing %+v", w)
+		}
+	}
+}
+
+func benchmarkFixtures(b *testing.B) map[string][]byte {
+	fixtures := map[string][]byte{}
+	for name, path := range map[string]string{
+		"apple": "../facts/fixtures/apple.html",
+		"nyt":   "./fixtures/nyt-20241231.htm",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.Fatalf("Failed to read fixture: %v", err)
+		}
+		// Skip fixtures that are still Git LFS pointers
+		if bytes.HasPrefix(data, []byte("version https://git-lfs")) {
+			continue
+		}
+		fixtures[name] = data
+	}
+	return fixtures
+}
+
+func BenchmarkParse(b *testing.B) {
+	for name, data := range benchmarkFixtures(b) {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, _, err := Parse(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseStream(b *testing.B) {
+	for name, data := range benchmarkFixtures(b) {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseStream(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,537 @@
+package ixbrl
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Cell is a single cell of a normalized table. Cells that EDGAR's
+// markup splits across several columns, like a "$" before an amount or
+// a ")" after a negative one, are merged back into one.
+type Cell struct {
+	// Text is the cell's displayed text, with whitespace collapsed,
+	// e.g. "$(1,234)"
+	Text string
+	// Header is true for cells in the table's header rows
+	Header bool
+	// Numeric is true when Text holds a number, parsed into Value
+	Numeric bool
+	Value   float64
+	// Currency is the currency symbol shown with a number, if any
+	Currency string
+	// Percent is true when a number is shown as a percentage; Value
+	// holds the number as shown, e.g. 12.5 for "12.5%"
+	Percent bool
+	// Node is the td or th element the cell's text came from
+	Node *html.Node
+}
+
+// Table is a normalized table: a grid of rows of cells, in which every
+// row has the same number of columns and header rows come first.
+type Table [][]Cell
+
+// maxSpan caps colspan and rowspan attributes, as browsers do
+const maxSpan = 1000
+
+// sourceCell is a td or th element; cells spanning several rows or
+// columns occupy several positions in the grid with the same pointer.
+type sourceCell struct {
+	node   *html.Node
+	text   string
+	header bool
+}
+
+// NormalizeTable turns a table element into a grid of cells: rowspans
+// and colspans are resolved, spacer columns and columns holding only
+// currency symbols or closing parentheses are merged into the columns
+// holding the values, blank rows are dropped, and numbers are parsed.
+// Leading rows that contain no values, other than years, are marked as
+// header rows.
+func NormalizeTable(node *html.Node) Table {
+	grid := tableGrid(node)
+	if len(grid) == 0 {
+		return nil
+	}
+
+	headerRows := countHeaderRows(grid)
+	body := grid[headerRows:]
+	if len(body) == 0 {
+		body = grid
+	}
+
+	groups := columnGroups(body, len(grid[0]))
+	if len(groups) == 0 {
+		return nil
+	}
+
+	table := make(Table, len(grid))
+	for i, row := range grid {
+		header := i < headerRows
+		table[i] = make([]Cell, len(groups))
+		for j, group := range groups {
+			table[i][j] = mergeCells(row, group, header)
+		}
+	}
+	return table
+}
+
+// Lines returns the lines of the cell's text, where its HTML breaks it
+// into several, e.g. an executive's name followed by their title.
+func (c Cell) Lines() []string {
+	if c.Node == nil {
+		return nil
+	}
+	return cellLines(c.Node)
+}
+
+// Headers returns a label for each column, joining the text of the
+// column's header cells from top to bottom, e.g. "Salary ($)".
+func (t Table) Headers() []string {
+	if len(t) == 0 {
+		return nil
+	}
+	headers := make([]string, len(t[0]))
+	for col := range headers {
+		var parts []string
+		for _, row := range t {
+			if !row[col].Header {
+				break
+			}
+			text := row[col].Text
+			if text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+				parts = append(parts, text)
+			}
+		}
+		headers[col] = strings.Join(parts, " ")
+	}
+	return headers
+}
+
+// Column returns the index of the first column whose header matches,
+// or -1 if there is none.
+func (t Table) Column(match func(header string) bool) int {
+	return slices.IndexFunc(t.Headers(), match)
+}
+
+// Body returns the table's rows below its header rows
+func (t Table) Body() Table {
+	for i, row := range t {
+		if len(row) > 0 && !row[0].Header {
+			return t[i:]
+		}
+	}
+	return nil
+}
+
+// tableGrid lays out a table's cells in a grid of rows and columns,
+// resolving rowspans and colspans. Blank rows are dropped.
+func tableGrid(table *html.Node) [][]*sourceCell {
+	type span struct {
+		cell *sourceCell
+		rows int
+	}
+	var spans []span
+	var grid [][]*sourceCell
+	width := 0
+
+	for _, tr := range tableRows(table) {
+		var row []*sourceCell
+		col := 0
+		// Fill in the cells of earlier rows spanning into this one
+		placeSpans := func() {
+			for col < len(spans) && spans[col].rows > 0 {
+				row = append(row, spans[col].cell)
+				spans[col].rows--
+				col++
+			}
+		}
+
+		for td := tr.FirstChild; td != nil; td = td.NextSibling {
+			if td.Type != html.ElementNode || (td.Data != "td" && td.Data != "th") {
+				continue
+			}
+			placeSpans()
+			cell := &sourceCell{
+				node:   td,
+				text:   cellText(td),
+				header: td.Data == "th" || (tr.Parent != nil && tr.Parent.Data == "thead"),
+			}
+			rowspan := spanAttr(td, "rowspan")
+			for range spanAttr(td, "colspan") {
+				row = append(row, cell)
+				for len(spans) <= col {
+					spans = append(spans, span{})
+				}
+				if rowspan > 1 {
+					spans[col] = span{cell: cell, rows: rowspan - 1}
+				}
+				col++
+			}
+		}
+		// Cells spanning from earlier rows may also follow this row's own
+		for ; col < len(spans); col++ {
+			if spans[col].rows > 0 {
+				row = append(row, spans[col].cell)
+				spans[col].rows--
+			} else {
+				row = append(row, nil)
+			}
+		}
+
+		if blankRow(row) {
+			continue
+		}
+		grid = append(grid, row)
+		width = max(width, len(row))
+	}
+
+	// Pad short rows, so every row has the same number of columns
+	for i := range grid {
+		for len(grid[i]) < width {
+			grid[i] = append(grid[i], nil)
+		}
+	}
+	return grid
+}
+
+// tableRows returns a table's tr elements, excluding those of any
+// tables nested within it.
+func tableRows(table *html.Node) []*html.Node {
+	var rows []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "tr":
+				rows = append(rows, child)
+			case "table":
+			default:
+				walk(child)
+			}
+		}
+	}
+	walk(table)
+	return rows
+}
+
+// cellText returns a cell's text with whitespace collapsed, separating
+// lines broken by br or block elements with a space
+func cellText(td *html.Node) string {
+	return strings.Join(cellLines(td), " ")
+}
+
+// cellLines returns the lines of a cell's text, as broken by br or block
+// elements, with whitespace collapsed
+func cellLines(td *html.Node) []string {
+	var lines []string
+	var b strings.Builder
+	breakLine := func() {
+		if line := strings.Join(strings.Fields(b.String()), " "); line != "" {
+			lines = append(lines, line)
+		}
+		b.Reset()
+	}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				b.WriteString(child.Data)
+			case html.ElementNode:
+				block := child.Data == "br" || !isInlineNode(child)
+				if block {
+					breakLine()
+				}
+				walk(child)
+				if block {
+					breakLine()
+				}
+			}
+		}
+	}
+	walk(td)
+	breakLine()
+	return lines
+}
+
+func spanAttr(node *html.Node, name string) int {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			n, err := strconv.Atoi(strings.TrimSpace(attr.Val))
+			if err != nil || n < 1 {
+				return 1
+			}
+			return min(n, maxSpan)
+		}
+	}
+	return 1
+}
+
+func blankRow(row []*sourceCell) bool {
+	for _, cell := range row {
+		if cell != nil && cell.text != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// countHeaderRows counts the leading rows of a grid that look like
+// headers: rows of th elements, or rows without any values other than
+// years, and with text in more than their first column.
+func countHeaderRows(grid [][]*sourceCell) int {
+	n := 0
+	for _, row := range grid {
+		if !headerRow(row) {
+			break
+		}
+		n++
+	}
+	// A table without any values is most likely a table of text, whose
+	// header can only be told apart by its markup
+	if n == len(grid) {
+		n = 0
+		for _, row := range grid {
+			if !markedHeaderRow(row) {
+				break
+			}
+			n++
+		}
+	}
+	return n
+}
+
+func markedHeaderRow(row []*sourceCell) bool {
+	for _, cell := range row {
+		if cell != nil && cell.text != "" && !cell.header {
+			return false
+		}
+	}
+	return true
+}
+
+var yearRegex = regexp.MustCompile(`^(19|20)\d{2}$`)
+
+func headerRow(row []*sourceCell) bool {
+	if markedHeaderRow(row) {
+		return true
+	}
+	filled := 0
+	for col, cell := range row {
+		if cell == nil || cell.text == "" || (col > 0 && row[col-1] == cell) {
+			continue
+		}
+		filled++
+		if prefixSymbol(cell.text) || suffixSymbol(cell.text) {
+			return false
+		}
+		if _, ok := parseCellNumber(cell.text); ok && !yearRegex.MatchString(cell.text) {
+			return false
+		}
+	}
+	// Rows labelling a section, like "Current assets:", aren't headers
+	return filled > 1 || (row[0] == nil || row[0].text == "")
+}
+
+// prefixSymbol reports whether text is a symbol that EDGAR tables put in
+// a column of its own before a number
+func prefixSymbol(text string) bool {
+	switch text {
+	case "$", "€", "£", "¥", "(", "$(", "($":
+		return true
+	}
+	return false
+}
+
+// suffixSymbol reports whether text is a symbol that EDGAR tables put in
+// a column of its own after a number
+func suffixSymbol(text string) bool {
+	switch text {
+	case ")", "%", ")%", "%)":
+		return true
+	}
+	return false
+}
+
+// columnGroup is a set of adjacent columns that are merged into one,
+// around the column holding the group's values.
+type columnGroup struct {
+	columns []int
+	content int
+}
+
+// columnGroups groups a grid's columns around the columns that hold
+// values in the given rows. Spacer and currency symbol columns join the
+// next group, and closing parenthesis and percent columns the previous.
+func columnGroups(rows [][]*sourceCell, width int) []columnGroup {
+	const (
+		empty = iota
+		content
+		prefix
+		suffix
+	)
+	roles := make([]int, width)
+	for col := range roles {
+		for _, row := range rows {
+			cell := row[col]
+			// Only consider cells starting in this column
+			if cell == nil || cell.text == "" || (col > 0 && row[col-1] == cell) {
+				continue
+			}
+			switch {
+			case prefixSymbol(cell.text):
+				if roles[col] == empty || roles[col] == suffix {
+					roles[col] = prefix
+				}
+			case suffixSymbol(cell.text):
+				if roles[col] == empty {
+					roles[col] = suffix
+				}
+			default:
+				roles[col] = content
+			}
+			if roles[col] == content {
+				break
+			}
+		}
+	}
+
+	var groups []columnGroup
+	var pending []int
+	for col, role := range roles {
+		switch {
+		case role == content:
+			groups = append(groups, columnGroup{columns: append(pending, col), content: col})
+			pending = nil
+		case role == suffix && len(groups) > 0:
+			last := &groups[len(groups)-1]
+			last.columns = append(append(last.columns, pending...), col)
+			pending = nil
+		default:
+			pending = append(pending, col)
+		}
+	}
+	if len(pending) > 0 && len(groups) > 0 {
+		last := &groups[len(groups)-1]
+		last.columns = append(last.columns, pending...)
+	}
+	return groups
+}
+
+// mergeCells merges the cells of a row in a group of columns into one.
+// Header cells spanning into the group from an earlier column are
+// repeated when they span its values, as they label every column they
+// span, but other cells are only kept in the group they start in.
+func mergeCells(row []*sourceCell, group columnGroup, header bool) Cell {
+	var parts []*sourceCell
+	first := group.columns[0]
+	for _, col := range group.columns {
+		cell := row[col]
+		if cell == nil || cell.text == "" || slices.Contains(parts, cell) {
+			continue
+		}
+		spilled := first > 0 && row[first-1] == cell
+		if spilled && !(header && row[group.content] == cell) {
+			continue
+		}
+		parts = append(parts, cell)
+	}
+
+	merged := Cell{Header: header}
+	var b strings.Builder
+	for i, part := range parts {
+		symbol := prefixSymbol(part.text) || suffixSymbol(part.text)
+		if i > 0 && !symbol && !prefixSymbol(parts[i-1].text) {
+			b.WriteString(" ")
+		}
+		b.WriteString(part.text)
+		if merged.Node == nil || !symbol {
+			merged.Node = part.node
+		}
+	}
+	merged.Text = b.String()
+
+	if number, ok := parseCellNumber(merged.Text); ok {
+		merged.Numeric = true
+		merged.Value = number.value
+		merged.Currency = number.currency
+		merged.Percent = number.percent
+	}
+	return merged
+}
+
+type cellNumber struct {
+	value    float64
+	currency string
+	percent  bool
+}
+
+var currencySymbols = []string{"$", "€", "£", "¥"}
+
+// footnoteRegex matches footnote markers trailing a number, e.g. the
+// "(1)" in "1,234(1)"
+var footnoteRegex = regexp.MustCompile(`\s*(\([a-zA-Z0-9]{1,2}\)|\*+)$`)
+
+// parseCellNumber parses the numbers shown in tables, like "$1,234",
+// "(1,234)" for negative numbers, "12.5%" and dashes meaning zero.
+func parseCellNumber(text string) (cellNumber, bool) {
+	number, ok := parseCellNumberText(text)
+	if !ok {
+		if stripped := footnoteRegex.ReplaceAllString(text, ""); stripped != text {
+			return parseCellNumberText(stripped)
+		}
+	}
+	return number, ok
+}
+
+func parseCellNumberText(text string) (cellNumber, bool) {
+	var number cellNumber
+	s := strings.TrimSpace(text)
+	if isDash(s) {
+		return number, true
+	}
+
+	for _, symbol := range currencySymbols {
+		if strings.Contains(s, symbol) {
+			number.currency = symbol
+			s = strings.ReplaceAll(s, symbol, "")
+		}
+	}
+	s = strings.TrimSpace(s)
+	if trimmed, ok := strings.CutSuffix(s, "%"); ok {
+		number.percent = true
+		s = strings.TrimSpace(trimmed)
+	}
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	for _, minus := range []string{"-", "−", "–"} {
+		if trimmed, ok := strings.CutPrefix(s, minus); ok {
+			negative = true
+			s = strings.TrimSpace(trimmed)
+			break
+		}
+	}
+	if s == "" || !strings.ContainsAny(s, "0123456789") {
+		return number, false
+	}
+
+	value, err := parseDotDecimal(s)
+	if err != nil {
+		return number, false
+	}
+	if negative {
+		value = -value
+	}
+	number.value = value
+	return number, true
+}
@@ -0,0 +1,156 @@
+package ixbrl
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// compensationTableHTML is laid out the way EDGAR filings usually are,
+// with spacer columns, currency symbols and closing parentheses in
+// cells of their own, and headers spanning them.
+const compensationTableHTML = `<table>
+<tr>
+	<td><b>Name and Principal Position</b></td>
+	<td></td>
+	<td><b>Year</b></td>
+	<td></td>
+	<td colspan="3"><b>Salary</b></td>
+	<td></td>
+	<td colspan="3"><b>Change in Value</b></td>
+</tr>
+<tr>
+	<td></td><td></td><td></td><td></td>
+	<td colspan="3">($)</td>
+	<td></td>
+	<td colspan="3">($)</td>
+</tr>
+<tr><td>&nbsp;</td><td></td><td></td><td></td><td></td><td></td><td></td><td></td><td></td><td></td><td></td></tr>
+<tr>
+	<td rowspan="2">Jane Doe<br/>Chief Executive Officer</td>
+	<td></td>
+	<td>2024</td>
+	<td></td>
+	<td>$</td><td>1,000,000</td><td></td>
+	<td></td>
+	<td>$</td><td>(25,000</td><td>)</td>
+</tr>
+<tr>
+	<td></td>
+	<td>2023</td>
+	<td></td>
+	<td></td><td>950,000<sup>(1)</sup></td><td></td>
+	<td></td>
+	<td></td><td>&#8212;</td><td></td>
+</tr>
+<tr>
+	<td colspan="3">Total</td>
+	<td></td>
+	<td>$</td><td>1,950,000</td><td></td>
+	<td></td>
+	<td></td><td>12.5</td><td>%</td>
+</tr>
+</table>`
+
+func parseTable(t *testing.T, content string) Table {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+	tables := FindTables(doc, func(string) bool { return true })
+	if len(tables) == 0 {
+		t.Fatal("Could not find table")
+	}
+	return NormalizeTable(tables[0])
+}
+
+func TestNormalizeTable(t *testing.T) {
+	table := parseTable(t, compensationTableHTML)
+
+	if len(table) != 5 {
+		t.Fatalf("expected 5 rows without the blank row, got %d", len(table))
+	}
+	for i, row := range table {
+		if len(row) != 4 {
+			t.Fatalf("row %d: expected 4 columns, got %d: %+v", i, len(row), row)
+		}
+	}
+
+	expectedHeaders := []string{"Name and Principal Position", "Year", "Salary ($)", "Change in Value ($)"}
+	headers := table.Headers()
+	for i, expected := range expectedHeaders {
+		if headers[i] != expected {
+			t.Errorf("column %d: expected header %q, got %q", i, expected, headers[i])
+		}
+	}
+
+	body := table.Body()
+	if len(body) != 3 {
+		t.Fatalf("expected 3 body rows, got %d", len(body))
+	}
+
+	expectedText := [][]string{
+		{"Jane Doe Chief Executive Officer", "2024", "$1,000,000", "$(25,000)"},
+		{"Jane Doe Chief Executive Officer", "2023", "950,000(1)", "—"},
+		{"Total", "", "$1,950,000", "12.5%"},
+	}
+	for i, row := range expectedText {
+		for j, expected := range row {
+			if body[i][j].Text != expected {
+				t.Errorf("cell %d,%d: expected %q, got %q", i, j, expected, body[i][j].Text)
+			}
+		}
+	}
+
+	salary := table.Column(func(header string) bool { return strings.HasPrefix(header, "Salary") })
+	if salary != 2 {
+		t.Fatalf("expected the salary column at 2, got %d", salary)
+	}
+	if cell := body[0][salary]; !cell.Numeric || cell.Value != 1000000 || cell.Currency != "$" {
+		t.Errorf("expected $1,000,000, got %+v", cell)
+	}
+	if cell := body[1][salary]; !cell.Numeric || cell.Value != 950000 {
+		t.Errorf("expected 950,000 with its footnote ignored, got %+v", cell)
+	}
+	if cell := body[0][3]; !cell.Numeric || cell.Value != -25000 {
+		t.Errorf("expected a negative value for parentheses, got %+v", cell)
+	}
+	if cell := body[1][3]; !cell.Numeric || cell.Value != 0 {
+		t.Errorf("expected zero for a dash, got %+v", cell)
+	}
+	if cell := body[2][3]; !cell.Numeric || !cell.Percent || cell.Value != 12.5 {
+		t.Errorf("expected 12.5%%, got %+v", cell)
+	}
+	if cell := body[0][0]; cell.Numeric || cell.Node == nil || cell.Node.Data != "td" {
+		t.Errorf("expected a text cell from a td, got %+v", cell)
+	}
+	if lines := body[0][0].Lines(); len(lines) != 2 || lines[0] != "Jane Doe" || lines[1] != "Chief Executive Officer" {
+		t.Errorf("expected the name and title on separate lines, got %q", lines)
+	}
+	if body[0][1].Numeric != true || body[0][1].Header {
+		t.Errorf("expected a numeric body cell for the year, got %+v", body[0][1])
+	}
+}
+
+func TestNormalizeTableTextOnly(t *testing.T) {
+	table := parseTable(t, `<table>
+		<thead><tr><th>Name</th><th>Title</th></tr></thead>
+		<tr><td>Jane Doe</td><td>Chief Executive Officer</td></tr>
+		<tr><td>John Roe</td><td>Chief Financial Officer</td></tr>
+	</table>`)
+
+	if len(table) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(table))
+	}
+	if !table[0][0].Header || table[1][0].Header {
+		t.Errorf("expected only the first row to be a header")
+	}
+	if headers := table.Headers(); strings.Join(headers, ",") != "Name,Title" {
+		t.Errorf("expected headers Name,Title, got %v", headers)
+	}
+	if text := table.Body()[1][1].Text; text != "Chief Financial Officer" {
+		t.Errorf("expected 'Chief Financial Officer', got %q", text)
+	}
+}
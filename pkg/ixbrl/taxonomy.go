@@ -0,0 +1,362 @@
+package ixbrl
+
+import (
+	"bytes"
+	"cmp"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Roles of the labels found in label linkbases
+const (
+	LabelRoleStandard = "http://www.xbrl.org/2003/role/label"
+	LabelRoleTerse    = "http://www.xbrl.org/2003/role/terseLabel"
+	LabelRoleVerbose  = "http://www.xbrl.org/2003/role/verboseLabel"
+	LabelRoleTotal    = "http://www.xbrl.org/2003/role/totalLabel"
+)
+
+//go:embed taxonomy/standard_lab.xml
+var standardLabels []byte
+
+// ConceptLabels are the human-readable labels of a fact's concept, e.g.
+// "Stock Repurchased During Period, Value" for
+// us-gaap:StockRepurchasedDuringPeriodValue
+type ConceptLabels struct {
+	Standard string `json:"standard,omitempty"`
+	Terse    string `json:"terse,omitempty"`
+}
+
+// label returns the terse label if there is one, then the standard label,
+// and otherwise the concept's name.
+func (l *ConceptLabels) label(name string) string {
+	switch {
+	case l == nil:
+		return name
+	case l.Terse != "":
+		return l.Terse
+	case l.Standard != "":
+		return l.Standard
+	default:
+		return name
+	}
+}
+
+// Calculation is a relationship from a calculation linkbase: within the
+// extended link role Role, the value of From is the sum of the values of
+// its To concepts, each multiplied by its Weight.
+type Calculation struct {
+	Role   string
+	From   string
+	To     string
+	Weight float64
+	Order  float64
+}
+
+// Taxonomy holds the labels and calculation relationships of concepts,
+// loaded from a filing's linkbases or the subset bundled with this package.
+type Taxonomy struct {
+	// labels maps concepts to their labels, by role
+	labels       map[string]map[string]string
+	Calculations []Calculation
+}
+
+// NewTaxonomy returns an empty taxonomy
+func NewTaxonomy() *Taxonomy {
+	return &Taxonomy{labels: map[string]map[string]string{}}
+}
+
+// DefaultTaxonomy returns a taxonomy holding the standard labels of the
+// us-gaap, dei and ecd concepts this project uses, to which a filing's
+// own linkbases can be added.
+func DefaultTaxonomy() *Taxonomy {
+	t := NewTaxonomy()
+	if err := t.AddLabelLinkbase(bytes.NewReader(standardLabels)); err != nil {
+		panic(fmt.Sprintf("failed to parse bundled labels: %v", err))
+	}
+	return t
+}
+
+// linkbaseXML is the shape of label and calculation linkbases. Elements
+// are matched by their local names, whatever prefix a filer uses.
+type linkbaseXML struct {
+	LabelLinks       []extendedLinkXML `xml:"labelLink"`
+	CalculationLinks []extendedLinkXML `xml:"calculationLink"`
+}
+
+type extendedLinkXML struct {
+	Role string `xml:"http://www.w3.org/1999/xlink role,attr"`
+	Locs []struct {
+		Href  string `xml:"http://www.w3.org/1999/xlink href,attr"`
+		Label string `xml:"http://www.w3.org/1999/xlink label,attr"`
+	} `xml:"loc"`
+	Labels []struct {
+		Label   string `xml:"http://www.w3.org/1999/xlink label,attr"`
+		Role    string `xml:"http://www.w3.org/1999/xlink role,attr"`
+		Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+		Content string `xml:",chardata"`
+	} `xml:"label"`
+	LabelArcs       []arcXML `xml:"labelArc"`
+	CalculationArcs []arcXML `xml:"calculationArc"`
+}
+
+type arcXML struct {
+	From   string `xml:"http://www.w3.org/1999/xlink from,attr"`
+	To     string `xml:"http://www.w3.org/1999/xlink to,attr"`
+	Weight string `xml:"weight,attr"`
+	Order  string `xml:"order,attr"`
+	Use    string `xml:"use,attr"`
+}
+
+// locators maps an extended link's locator labels to the concepts they
+// point to
+func (l extendedLinkXML) locators() map[string][]string {
+	locs := map[string][]string{}
+	for _, loc := range l.Locs {
+		locs[loc.Label] = append(locs[loc.Label], hrefConcept(loc.Href))
+	}
+	return locs
+}
+
+// hrefConcept converts a locator's href to a concept's QName: the
+// fragment identifies the concept's element as "prefix_Name", e.g.
+// "us-gaap-2024.xsd#us-gaap_Revenues" is us-gaap:Revenues.
+func hrefConcept(href string) string {
+	_, fragment, _ := strings.Cut(href, "#")
+	prefix, name, found := strings.Cut(fragment, "_")
+	if !found {
+		return fragment
+	}
+	return prefix + ":" + name
+}
+
+func parseLinkbase(r io.Reader) (linkbaseXML, error) {
+	var linkbase linkbaseXML
+	if err := xml.NewDecoder(r).Decode(&linkbase); err != nil {
+		return linkbase, fmt.Errorf("failed to parse linkbase: %w", err)
+	}
+	return linkbase, nil
+}
+
+// AddLabelLinkbase loads the English labels from a label linkbase. They
+// take precedence over any labels loaded before.
+func (t *Taxonomy) AddLabelLinkbase(r io.Reader) error {
+	linkbase, err := parseLinkbase(r)
+	if err != nil {
+		return err
+	}
+	for _, link := range linkbase.LabelLinks {
+		locs := link.locators()
+		for _, arc := range link.LabelArcs {
+			if arc.Use == "prohibited" {
+				continue
+			}
+			for _, label := range link.Labels {
+				lang := strings.ToLower(label.Lang)
+				if label.Label != arc.To || (lang != "" && lang != "en" && !strings.HasPrefix(lang, "en-")) {
+					continue
+				}
+				role := label.Role
+				if role == "" {
+					role = LabelRoleStandard
+				}
+				for _, concept := range locs[arc.From] {
+					if t.labels[concept] == nil {
+						t.labels[concept] = map[string]string{}
+					}
+					t.labels[concept][role] = strings.Join(strings.Fields(label.Content), " ")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AddCalculationLinkbase loads the relationships from a calculation
+// linkbase
+func (t *Taxonomy) AddCalculationLinkbase(r io.Reader) error {
+	linkbase, err := parseLinkbase(r)
+	if err != nil {
+		return err
+	}
+	for _, link := range linkbase.CalculationLinks {
+		locs := link.locators()
+		for _, arc := range link.CalculationArcs {
+			if arc.Use == "prohibited" {
+				continue
+			}
+			weight, err := strconv.ParseFloat(strings.TrimSpace(arc.Weight), 64)
+			if err != nil {
+				return fmt.Errorf("failed to parse calculation weight %q: %w", arc.Weight, err)
+			}
+			order, _ := strconv.ParseFloat(strings.TrimSpace(arc.Order), 64)
+			for _, from := range locs[arc.From] {
+				for _, to := range locs[arc.To] {
+					t.Calculations = append(t.Calculations, Calculation{
+						Role:   link.Role,
+						From:   from,
+						To:     to,
+						Weight: weight,
+						Order:  order,
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Label returns a concept's label with the given role, if it has one
+func (t *Taxonomy) Label(concept, role string) string {
+	return t.labels[concept][role]
+}
+
+// Labels returns a concept's standard and terse labels, or nil if the
+// taxonomy has neither.
+func (t *Taxonomy) Labels(concept string) *ConceptLabels {
+	labels := ConceptLabels{
+		Standard: t.Label(concept, LabelRoleStandard),
+		Terse:    t.Label(concept, LabelRoleTerse),
+	}
+	if labels == (ConceptLabels{}) {
+		return nil
+	}
+	return &labels
+}
+
+// Children returns the calculation relationships summing up a concept
+// within an extended link role, in order.
+func (t *Taxonomy) Children(role, concept string) []Calculation {
+	var children []Calculation
+	for _, c := range t.Calculations {
+		if c.Role == role && c.From == concept {
+			children = append(children, c)
+		}
+	}
+	slices.SortStableFunc(children, func(a, b Calculation) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+	return children
+}
+
+// ApplyTaxonomy sets the labels of the document's facts
+func (d *Document) ApplyTaxonomy(t *Taxonomy) {
+	for _, p := range d.Nodes {
+		switch n := p.Struct.(type) {
+		case *NonFraction:
+			n.Labels = t.Labels(n.Name)
+		case *NonNumeric:
+			n.Labels = t.Labels(n.Name)
+		case *Fraction:
+			n.Labels = t.Labels(n.Name)
+		}
+	}
+}
+
+// CalculationInconsistency is a numeric fact that doesn't equal the
+// weighted sum of the facts its calculation relationships sum up, in the
+// same context and unit.
+type CalculationInconsistency struct {
+	Role     string
+	Parent   *NonFraction
+	Children []*NonFraction
+	// Sum is the weighted sum of the children's values
+	Sum float64
+}
+
+// CheckCalculations checks the document's numeric facts against the
+// taxonomy's calculation relationships. As in XBRL Calculations 1.1, a
+// sum is consistent if it's within the rounding of the facts involved,
+// given their decimals, and sums without any child facts are skipped.
+func (t *Taxonomy) CheckCalculations(d *Document) []CalculationInconsistency {
+	type key struct{ concept, context, unit string }
+	facts := map[key]*NonFraction{}
+	var ordered []key
+	for _, p := range d.Nodes {
+		nf, ok := p.Struct.(*NonFraction)
+		if !ok {
+			continue
+		}
+		if _, err := nf.ScaledValue(); err != nil {
+			continue
+		}
+		k := key{nf.Name, nf.ContextRef, nf.UnitRef}
+		if _, seen := facts[k]; !seen {
+			facts[k] = nf
+			ordered = append(ordered, k)
+		}
+	}
+
+	type parent struct{ role, concept string }
+	var parents []parent
+	seen := map[parent]bool{}
+	for _, c := range t.Calculations {
+		p := parent{c.Role, c.From}
+		if !seen[p] {
+			seen[p] = true
+			parents = append(parents, p)
+		}
+	}
+
+	var inconsistencies []CalculationInconsistency
+	for _, p := range parents {
+		children := t.Children(p.role, p.concept)
+		for _, k := range ordered {
+			if k.concept != p.concept {
+				continue
+			}
+			total := facts[k]
+			value, _ := total.ScaledValue()
+			tolerance := roundingTolerance(total.Decimals)
+
+			inconsistency := CalculationInconsistency{Role: p.role, Parent: total}
+			for _, c := range children {
+				child, ok := facts[key{c.To, k.context, k.unit}]
+				if !ok {
+					continue
+				}
+				childValue, _ := child.ScaledValue()
+				inconsistency.Sum += c.Weight * childValue
+				inconsistency.Children = append(inconsistency.Children, child)
+				tolerance += math.Abs(c.Weight) * roundingTolerance(child.Decimals)
+			}
+			if len(inconsistency.Children) > 0 && math.Abs(value-inconsistency.Sum) > tolerance {
+				inconsistencies = append(inconsistencies, inconsistency)
+			}
+		}
+	}
+	return inconsistencies
+}
+
+// roundingTolerance is the most a value reported with the given decimals
+// attribute can differ from the value it was rounded from
+func roundingTolerance(decimals string) float64 {
+	d, err := strconv.Atoi(strings.TrimSpace(decimals))
+	if err != nil {
+		return 0
+	}
+	return 0.5 * math.Pow10(-d)
+}
+
+// Label returns the fact's terse label, or else its standard label, or
+// else the name of its concept
+func (nf *NonFraction) Label() string {
+	return nf.Labels.label(nf.Name)
+}
+
+// Label returns the fact's terse label, or else its standard label, or
+// else the name of its concept
+func (nn *NonNumeric) Label() string {
+	return nn.Labels.label(nn.Name)
+}
+
+// Label returns the fact's terse label, or else its standard label, or
+// else the name of its concept
+func (f *Fraction) Label() string {
+	return f.Labels.label(f.Name)
+}
@@ -0,0 +1,117 @@
+package ixbrl
+
+import (
+	"strings"
+	"testing"
+)
+
+const labelLinkbaseXML = `<?xml version="1.0" encoding="UTF-8"?>
+<linkbase xmlns="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+	<labelLink xlink:type="extended" xlink:role="http://www.xbrl.org/2003/role/link">
+		<loc xlink:type="locator" xlink:href="https://xbrl.fasb.org/us-gaap/2024/elts/us-gaap-2024.xsd#us-gaap_Revenues" xlink:label="loc_Revenues"/>
+		<label xlink:type="resource" xlink:label="lab_Revenues" xlink:role="http://www.xbrl.org/2003/role/label" xml:lang="en-US">Total net sales</label>
+		<label xlink:type="resource" xlink:label="lab_Revenues" xlink:role="http://www.xbrl.org/2003/role/terseLabel" xml:lang="en-US">Net sales</label>
+		<label xlink:type="resource" xlink:label="lab_Revenues" xlink:role="http://www.xbrl.org/2003/role/terseLabel" xml:lang="fr">Ventes nettes</label>
+		<labelArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/concept-label" xlink:from="loc_Revenues" xlink:to="lab_Revenues"/>
+		<loc xlink:type="locator" xlink:href="test-20241231.xsd#test_WidgetSales" xlink:label="loc_WidgetSales"/>
+		<label xlink:type="resource" xlink:label="lab_WidgetSales" xlink:role="http://www.xbrl.org/2003/role/label" xml:lang="en-US">Widget
+			Sales</label>
+		<labelArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/concept-label" xlink:from="loc_WidgetSales" xlink:to="lab_WidgetSales"/>
+	</labelLink>
+</linkbase>`
+
+const calculationLinkbaseXML = `<?xml version="1.0" encoding="UTF-8"?>
+<link:linkbase xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+	<link:calculationLink xlink:type="extended" xlink:role="http://test/role/IncomeStatement">
+		<link:loc xlink:type="locator" xlink:href="https://xbrl.fasb.org/us-gaap/2024/elts/us-gaap-2024.xsd#us-gaap_GrossProfit" xlink:label="loc_GrossProfit"/>
+		<link:loc xlink:type="locator" xlink:href="https://xbrl.fasb.org/us-gaap/2024/elts/us-gaap-2024.xsd#us-gaap_Revenues" xlink:label="loc_Revenues"/>
+		<link:loc xlink:type="locator" xlink:href="https://xbrl.fasb.org/us-gaap/2024/elts/us-gaap-2024.xsd#us-gaap_CostOfRevenue" xlink:label="loc_CostOfRevenue"/>
+		<link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="loc_GrossProfit" xlink:to="loc_CostOfRevenue" order="2" weight="-1"/>
+		<link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="loc_GrossProfit" xlink:to="loc_Revenues" order="1" weight="1.0"/>
+	</link:calculationLink>
+</link:linkbase>`
+
+func TestTaxonomyLabels(t *testing.T) {
+	taxonomy := DefaultTaxonomy()
+	if label := taxonomy.Label("us-gaap:StockRepurchasedDuringPeriodValue", LabelRoleStandard); label != "Stock Repurchased During Period, Value" {
+		t.Errorf("expected a bundled standard label, got %q", label)
+	}
+
+	if err := taxonomy.AddLabelLinkbase(strings.NewReader(labelLinkbaseXML)); err != nil {
+		t.Fatalf("AddLabelLinkbase failed: %v", err)
+	}
+	labels := taxonomy.Labels("us-gaap:Revenues")
+	if labels == nil || labels.Standard != "Total net sales" || labels.Terse != "Net sales" {
+		t.Errorf("expected the filing's English labels to replace the bundled ones, got %+v", labels)
+	}
+	if label := taxonomy.Label("test:WidgetSales", LabelRoleStandard); label != "Widget Sales" {
+		t.Errorf("expected a label for the company's own concept, got %q", label)
+	}
+	if labels := taxonomy.Labels("test:Unknown"); labels != nil {
+		t.Errorf("expected no labels for an unknown concept, got %+v", labels)
+	}
+	if err := taxonomy.AddLabelLinkbase(strings.NewReader("<linkbase")); err == nil {
+		t.Errorf("expected an error for a malformed linkbase")
+	}
+}
+
+func TestApplyTaxonomy(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(`<html><body>
+		<xbrli:context id="c-1"><xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period></xbrli:context>
+		<p><ix:nonFraction name="us-gaap:Revenues" contextRef="c-1" unitRef="usd">100</ix:nonFraction></p>
+		<p><ix:nonFraction name="test:Other" contextRef="c-1" unitRef="usd">5</ix:nonFraction></p>
+	</body></html>`))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	taxonomy := DefaultTaxonomy()
+	if err := taxonomy.AddLabelLinkbase(strings.NewReader(labelLinkbaseXML)); err != nil {
+		t.Fatalf("AddLabelLinkbase failed: %v", err)
+	}
+	doc.ApplyTaxonomy(taxonomy)
+
+	revenues := doc.Undimensioned("us-gaap:Revenues", nil)
+	if revenues == nil || revenues.Labels == nil || revenues.Labels.Standard != "Total net sales" {
+		t.Fatalf("expected labels on the revenues fact, got %+v", revenues)
+	}
+	if revenues.Label() != "Net sales" {
+		t.Errorf("expected Label to prefer the terse label, got %q", revenues.Label())
+	}
+	if other := doc.Undimensioned("test:Other", nil); other.Label() != "test:Other" {
+		t.Errorf("expected Label to fall back to the concept name, got %q", other.Label())
+	}
+}
+
+func TestCheckCalculations(t *testing.T) {
+	taxonomy := NewTaxonomy()
+	if err := taxonomy.AddCalculationLinkbase(strings.NewReader(calculationLinkbaseXML)); err != nil {
+		t.Fatalf("AddCalculationLinkbase failed: %v", err)
+	}
+	children := taxonomy.Children("http://test/role/IncomeStatement", "us-gaap:GrossProfit")
+	if len(children) != 2 || children[0].To != "us-gaap:Revenues" || children[1].Weight != -1 {
+		t.Fatalf("expected revenues less cost of revenue, got %+v", children)
+	}
+
+	doc, err := ParseDocument(strings.NewReader(`<html><body>
+		<xbrli:context id="FY2024"><xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period></xbrli:context>
+		<xbrli:context id="FY2023"><xbrli:period><xbrli:startDate>2023-01-01</xbrli:startDate><xbrli:endDate>2023-12-31</xbrli:endDate></xbrli:period></xbrli:context>
+		<p><ix:nonFraction name="us-gaap:Revenues" contextRef="FY2024" unitRef="usd" decimals="-6" scale="6">1,000</ix:nonFraction></p>
+		<p><ix:nonFraction name="us-gaap:CostOfRevenue" contextRef="FY2024" unitRef="usd" decimals="-6" scale="6">600</ix:nonFraction></p>
+		<p><ix:nonFraction name="us-gaap:GrossProfit" contextRef="FY2024" unitRef="usd" decimals="-6" scale="6">400</ix:nonFraction></p>
+		<p><ix:nonFraction name="us-gaap:Revenues" contextRef="FY2023" unitRef="usd" decimals="-6" scale="6">900</ix:nonFraction></p>
+		<p><ix:nonFraction name="us-gaap:CostOfRevenue" contextRef="FY2023" unitRef="usd" decimals="-6" scale="6">500</ix:nonFraction></p>
+		<p><ix:nonFraction name="us-gaap:GrossProfit" contextRef="FY2023" unitRef="usd" decimals="-6" scale="6">450</ix:nonFraction></p>
+	</body></html>`))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	inconsistencies := taxonomy.CheckCalculations(doc)
+	if len(inconsistencies) != 1 {
+		t.Fatalf("expected 1 inconsistency, got %d", len(inconsistencies))
+	}
+	inconsistency := inconsistencies[0]
+	if inconsistency.Parent.ContextRef != "FY2023" || inconsistency.Sum != 400000000 || len(inconsistency.Children) != 2 {
+		t.Errorf("expected FY2023 gross profit to be inconsistent with a sum of 400000000, got %+v", inconsistency)
+	}
+}
@@ -0,0 +1,239 @@
+package ixbrl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Errors returned when a fact's displayed content can't be converted to a value
+var (
+	ErrUnsupportedFormat = errors.New("unsupported iXBRL format")
+	ErrInvalidNumber     = errors.New("invalid number for iXBRL format")
+	ErrInvalidDate       = errors.New("invalid date for iXBRL format")
+	ErrNilValue          = errors.New("iXBRL fact is nil")
+)
+
+// transforms implements the numeric formats of the Inline XBRL
+// Transformation Registry (versions 1 to 5) and the SEC's own ixt-sec
+// registry, keyed by the format's local name. Several names refer to
+// the same rule, as the registries renamed them over time.
+var transforms = map[string]func(string) (float64, error){
+	// 1,234,567.89 and 1 234 567.89
+	"num-dot-decimal": parseDotDecimal,
+	"numdotdecimal":   parseDotDecimal,
+	"numcommadot":     parseDotDecimal,
+	"numspacedot":     parseDotDecimal,
+	// 1.234.567,89 and 1 234 567,89
+	"num-comma-decimal": parseCommaDecimal,
+	"numcommadecimal":   parseCommaDecimal,
+	"numdotcomma":       parseCommaDecimal,
+	"numspacecomma":     parseCommaDecimal,
+	// 12 dollars 50 cents
+	"num-unit-decimal": parseUnitDecimal,
+	"numunitdecimal":   parseUnitDecimal,
+	// Any content, usually a dash, meaning zero
+	"fixed-zero": parseFixedZero,
+	"fixedzero":  parseFixedZero,
+	"zerodash":   parseFixedZero,
+	"numdash":    parseFixedZero,
+	// Numbers written out in English, e.g. "no" or "twenty-five"
+	"numwordsen":  parseWordsEN,
+	"num-word-en": parseWordsEN,
+}
+
+// ParseNumber converts the displayed content of a numeric fact to its
+// value according to its format attribute, e.g. "ixt:num-dot-decimal".
+// The result is unsigned and unscaled, as the sign and scale attributes
+// are applied separately. Without a format, content is read as a plain
+// number with optional thousands separators.
+func ParseNumber(format, content string) (float64, error) {
+	content = strings.TrimSpace(content)
+	name := format
+	if _, local, found := strings.Cut(format, ":"); found {
+		name = local
+	}
+	name = strings.ToLower(name)
+
+	if name == "" {
+		// Dashes are commonly used to mean zero even without a format
+		if isDash(content) {
+			return 0, nil
+		}
+		return parseDotDecimal(content)
+	}
+	transform, ok := transforms[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	return transform(content)
+}
+
+func isDash(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.Is(unicode.Pd, r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isGrouping reports whether r is a thousands separator in the given
+// decimal convention; spaces, including non-breaking ones, and apostrophes
+// may be used in either convention.
+func isGrouping(r rune, decimal rune) bool {
+	if unicode.IsSpace(r) || r == '\'' {
+		return true
+	}
+	if decimal == '.' {
+		return r == ','
+	}
+	return r == '.'
+}
+
+func parseDecimal(content string, decimal rune) (float64, error) {
+	var b strings.Builder
+	for _, r := range content {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == decimal:
+			b.WriteRune('.')
+		case isGrouping(r, decimal):
+		default:
+			return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, content)
+		}
+	}
+	value, err := strconv.ParseFloat(b.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, content)
+	}
+	return value, nil
+}
+
+func parseDotDecimal(content string) (float64, error) {
+	return parseDecimal(content, '.')
+}
+
+func parseCommaDecimal(content string) (float64, error) {
+	return parseDecimal(content, ',')
+}
+
+// parseUnitDecimal reads a whole number and a fraction separated by unit
+// names, e.g. "12 dollars 50 cents" or "5 Euro 05".
+func parseUnitDecimal(content string) (float64, error) {
+	groups := strings.FieldsFunc(content, func(r rune) bool {
+		return !(r >= '0' && r <= '9') && r != ',' && r != '.'
+	})
+	if len(groups) == 0 || len(groups) > 2 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, content)
+	}
+	whole := strings.NewReplacer(",", "", ".", "").Replace(groups[0])
+	number := whole
+	if len(groups) == 2 {
+		number += "." + groups[1]
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, content)
+	}
+	return value, nil
+}
+
+func parseFixedZero(string) (float64, error) {
+	return 0, nil
+}
+
+var wordNumbers = map[string]float64{
+	"no": 0, "none": 0, "zero": 0, "a": 1, "an": 1, "one": 1, "two": 2,
+	"three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8,
+	"nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13,
+	"fourteen": 14, "fifteen": 15, "sixteen": 16, "seventeen": 17,
+	"eighteen": 18, "nineteen": 19, "twenty": 20, "thirty": 30, "forty": 40,
+	"fifty": 50, "sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+var wordMultipliers = map[string]float64{
+	"hundred": 100, "thousand": 1e3, "million": 1e6, "billion": 1e9, "trillion": 1e12,
+}
+
+// parseWordsEN reads numbers written out in English, such as "no",
+// "twenty-five" or "one hundred and two".
+func parseWordsEN(content string) (float64, error) {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == ','
+	})
+	if len(words) == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, content)
+	}
+	var total, current float64
+	for _, word := range words {
+		if word == "and" {
+			continue
+		}
+		if n, ok := wordNumbers[word]; ok {
+			current += n
+			continue
+		}
+		m, ok := wordMultipliers[word]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, content)
+		}
+		if current == 0 {
+			current = 1
+		}
+		if m == 100 {
+			current *= m
+		} else {
+			total += current * m
+			current = 0
+		}
+	}
+	return total + current, nil
+}
+
+// dateLayouts maps the date formats of the transformation registries to
+// Go time layouts, tried in order, for the formats used on EDGAR's cover
+// pages.
+var dateLayouts = map[string][]string{
+	"date-monthname-day-year-en": {"January 2, 2006", "January 2 2006", "Jan. 2, 2006", "Jan 2, 2006"},
+	"datemonthdayyearen":         {"January 2, 2006", "January 2 2006", "Jan. 2, 2006", "Jan 2, 2006"},
+	"date-day-monthname-year-en": {"2 January 2006", "2 Jan 2006"},
+	"datedaymonthyearen":         {"2 January 2006", "2 Jan 2006"},
+	"date-month-day-year":        {"1/2/2006", "1-2-2006", "1.2.2006", "1/2/06"},
+	"datemonthdayyear":           {"1/2/2006", "1-2-2006", "1.2.2006", "1/2/06"},
+	"dateslashus":                {"1/2/2006", "1/2/06"},
+	"date-year-month-day":        {"2006-01-02", "2006/1/2", "2006.1.2"},
+	"dateyearmonthday":           {"2006-01-02", "2006/1/2", "2006.1.2"},
+}
+
+// ParseDate converts the displayed content of a date fact to ISO 8601
+// ("2006-01-02") according to its format attribute, e.g.
+// "ixt:date-monthname-day-year-en". Without a format, content is
+// expected to be in ISO 8601 already.
+func ParseDate(format, content string) (string, error) {
+	content = strings.Join(strings.Fields(content), " ")
+	name := format
+	if _, local, found := strings.Cut(format, ":"); found {
+		name = local
+	}
+	layouts := []string{"2006-01-02"}
+	if name != "" {
+		var ok bool
+		if layouts, ok = dateLayouts[strings.ToLower(name)]; !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+		}
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, content); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidDate, content)
+}
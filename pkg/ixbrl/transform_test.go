@@ -0,0 +1,76 @@
+package ixbrl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		format   string
+		content  string
+		expected float64
+	}{
+		{"", "1,234", 1234},
+		{"", "—", 0},
+		{"ixt:num-dot-decimal", "1,234,567.89", 1234567.89},
+		{"ixt:numdotdecimal", "1 234.5", 1234.5},
+		{"ixt:num-comma-decimal", "1.234.567,89", 1234567.89},
+		{"ixt:numdotcomma", "12,5", 12.5},
+		{"ixt:fixed-zero", "—", 0},
+		{"ixt:zerodash", "-", 0},
+		{"ixt:num-unit-decimal", "12 dollars 50 cents", 12.50},
+		{"ixt-sec:numwordsen", "no", 0},
+		{"ixt-sec:numwordsen", "Twenty-five", 25},
+		{"ixt-sec:numwordsen", "one hundred and two thousand", 102000},
+	}
+	for _, test := range tests {
+		value, err := ParseNumber(test.format, test.content)
+		if err != nil {
+			t.Errorf("ParseNumber(%q, %q) failed: %v", test.format, test.content, err)
+			continue
+		}
+		if value != test.expected {
+			t.Errorf("ParseNumber(%q, %q) = %f, expected %f", test.format, test.content, value, test.expected)
+		}
+	}
+}
+
+func TestParseNumberErrors(t *testing.T) {
+	if _, err := ParseNumber("ixt:date-monthname-day-year-en", "May 1, 2024"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := ParseNumber("ixt:num-dot-decimal", "n/a"); !errors.Is(err, ErrInvalidNumber) {
+		t.Errorf("Expected ErrInvalidNumber, got %v", err)
+	}
+}
+
+func TestScaledValueSign(t *testing.T) {
+	tests := []struct {
+		nf       NonFraction
+		expected float64
+	}{
+		{NonFraction{Content: "1,234", Scale: "3", Sign: "-", Format: "ixt:num-dot-decimal"}, -1234000},
+		{NonFraction{Content: "(56)", Scale: "6"}, -56000000},
+		{NonFraction{Content: "-78", Scale: "0"}, -78},
+		{NonFraction{Content: "—", Scale: "6", Format: "ixt:fixed-zero"}, 0},
+	}
+	for _, test := range tests {
+		value, err := test.nf.ScaledValue()
+		if err != nil {
+			t.Errorf("ScaledValue(%q) failed: %v", test.nf.Content, err)
+			continue
+		}
+		if value != test.expected {
+			t.Errorf("ScaledValue(%q) = %f, expected %f", test.nf.Content, value, test.expected)
+		}
+	}
+
+	nf := NonFraction{Content: "", Nil: "true"}
+	if _, err := nf.ScaledValue(); !errors.Is(err, ErrNilValue) {
+		t.Errorf("Expected ErrNilValue, got %v", err)
+	}
+	if value := nf.ScaledNumber(); value != 0 {
+		t.Errorf("Expected ScaledNumber of a nil fact to be 0, got %f", value)
+	}
+}